@@ -1,3 +1,6 @@
+//go:build !software
+// +build !software
+
 // Package bcmhost contains bindings for the functions declared in bcm_host.h
 // (and its includes).
 // TODO(robert): Consider refactoring this package to just bind dispmanx.
@@ -153,6 +156,16 @@ func (u DispmanxUpdate) ElementAdd(
 	return DispmanxElement{u, handle}, nil
 }
 
+// ElementRemove wraps vc_dispmanx_element_remove, removing element from the
+// display when u is submitted.
+func (u DispmanxUpdate) ElementRemove(element DispmanxElement) error {
+	result := C.vc_dispmanx_element_remove(u.handle, element.handle)
+	if result != 0 {
+		return errors.New("could not remove element in display update")
+	}
+	return nil
+}
+
 type Rect struct {
 	X, Y, Width, Height int
 }
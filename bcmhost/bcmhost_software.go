@@ -0,0 +1,135 @@
+//go:build software
+// +build software
+
+package bcmhost
+
+import (
+	"errors"
+	"fmt"
+
+	"../egl"
+)
+
+// The software backend stands in for the VideoCore host so that the setup code
+// in main runs unchanged off the device. The display has a fixed size that
+// tests may change before calling GraphicsGetDisplaySize.
+var (
+	DisplayWidth  = 1920
+	DisplayHeight = 1080
+)
+
+// Consts from Dispmanx.
+const (
+	DispmanxIDMainLcd       = 0
+	DispmanxProtectionNone  = 0
+	DispmanxDefaultResource = 0
+)
+
+type Protection int
+
+const (
+	ProtectionNone Protection = 0
+)
+
+// Init wraps bcm_host_init.
+func Init() {}
+
+// Deinit wraps bcm_host_deinit.
+func Deinit() {}
+
+// GraphicsGetDisplaySize wraps graphics_get_display_size and returns width and
+// height.
+func GraphicsGetDisplaySize(display int) (int, int, error) {
+	if display != DispmanxIDMainLcd {
+		return 0, 0, fmt.Errorf("could not get display size for display \"%d\"", display)
+	}
+	return DisplayWidth, DisplayHeight, nil
+}
+
+// DispmanxDisplay represents a handle to a Display.
+type DispmanxDisplay struct {
+	id int
+}
+
+// DispmanxDisplayOpen wraps vc_dispmanx_display_open.
+func DispmanxDisplayOpen(display int) (DispmanxDisplay, error) {
+	if display != DispmanxIDMainLcd {
+		return DispmanxDisplay{}, fmt.Errorf("could not open display \"%d\"", display)
+	}
+	return DispmanxDisplay{display}, nil
+}
+
+// Close closes a Display.
+func (d DispmanxDisplay) Close() error {
+	return nil
+}
+
+// DispmanxUpdate represents a handle to a Display update.
+type DispmanxUpdate struct {
+	display DispmanxDisplay
+}
+
+// UpdateStart starts an update.
+func (d DispmanxDisplay) UpdateStart(priority int) (DispmanxUpdate, error) {
+	return DispmanxUpdate{d}, nil
+}
+
+func (d DispmanxUpdate) UpdateSubmit() error {
+	return nil
+}
+
+type DispmanxElement struct {
+	update DispmanxUpdate
+	handle *softElement
+}
+
+type softElement struct {
+	dest    Rect
+	removed bool
+}
+
+// elementCount is the number of elements added and not yet removed.
+var elementCount int
+
+// ElementCount returns the number of elements added and not yet removed, so
+// that tests can check their setup code cleans up after itself.
+func ElementCount() int {
+	return elementCount
+}
+
+func (u DispmanxUpdate) ElementAdd(
+	layer int, dest Rect, srcHandle int, src Rect, protection Protection) (DispmanxElement, error) {
+	if dest.Width <= 0 || dest.Height <= 0 {
+		return DispmanxElement{}, errors.New("could not add element to display update")
+	}
+	elementCount++
+	return DispmanxElement{u, &softElement{dest: dest}}, nil
+}
+
+// ElementRemove wraps vc_dispmanx_element_remove, removing element from the
+// display when u is submitted.
+func (u DispmanxUpdate) ElementRemove(element DispmanxElement) error {
+	if element.handle == nil || element.handle.removed {
+		return errors.New("could not remove element in display update")
+	}
+	element.handle.removed = true
+	elementCount--
+	return nil
+}
+
+type Rect struct {
+	X, Y, Width, Height int
+}
+
+// DispmanxWindow implements egl.NativeWindow.
+type DispmanxWindow struct {
+	handle egl.NativeWindowHandle
+}
+
+func NewDispmanxWindow(element DispmanxElement, w, h int) DispmanxWindow {
+	return DispmanxWindow{egl.NativeWindowHandle{Width: w, Height: h}}
+}
+
+func (w DispmanxWindow) Handle() egl.NativeWindowHandle {
+	return w.handle
+}
@@ -0,0 +1,83 @@
+//go:build !software
+// +build !software
+
+package egl
+
+// #include "EGL/egl.h"
+import "C"
+import "fmt"
+
+// Config wraps an EGLConfig of a Display.
+type Config struct {
+	display C.EGLDisplay
+	handle  C.EGLConfig
+}
+
+// GetConfigs wraps eglGetConfigs, returning every config of d.
+func (d Display) GetConfigs() ([]Config, error) {
+	var n C.EGLint
+	if C.eglGetConfigs(d.handle, nil, 0, &n) == C.EGL_FALSE {
+		return nil, lastError("failed to get configs")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	handles := make([]C.EGLConfig, n)
+	if C.eglGetConfigs(d.handle, &handles[0], n, &n) == C.EGL_FALSE {
+		return nil, lastError("failed to get configs")
+	}
+	return d.configs(handles[:n]), nil
+}
+
+// ChooseConfigs wraps eglChooseConfig, returning every config of d matching
+// attribs in EGL's order of preference. Nil attribs are the same as
+// NewConfigAttribs().
+func (d Display) ChooseConfigs(attribs *ConfigAttribs) ([]Config, error) {
+	l := attribs.list()
+	list := make([]C.EGLint, len(l))
+	for i, v := range l {
+		list[i] = C.EGLint(v)
+	}
+	var n C.EGLint
+	if C.eglChooseConfig(d.handle, &list[0], nil, 0, &n) == C.EGL_FALSE {
+		return nil, lastError("failed to choose configs")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	handles := make([]C.EGLConfig, n)
+	if C.eglChooseConfig(d.handle, &list[0], &handles[0], n, &n) == C.EGL_FALSE {
+		return nil, lastError("failed to choose configs")
+	}
+	return d.configs(handles[:n]), nil
+}
+
+func (d Display) configs(handles []C.EGLConfig) []Config {
+	configs := make([]Config, len(handles))
+	for i, h := range handles {
+		configs[i] = Config{d.handle, h}
+	}
+	return configs
+}
+
+// ChooseConfig returns the config EGL prefers among those matching
+// DefaultConfigAttribs.
+func (d Display) ChooseConfig() (Config, error) {
+	configs, err := d.ChooseConfigs(DefaultConfigAttribs())
+	if err != nil {
+		return Config{}, err
+	}
+	if len(configs) == 0 {
+		return Config{}, ErrNoMatchingConfig
+	}
+	return configs[0], nil
+}
+
+// Attrib wraps eglGetConfigAttrib.
+func (c Config) Attrib(attr Attrib) (int, error) {
+	var v C.EGLint
+	if C.eglGetConfigAttrib(c.display, c.handle, C.EGLint(attr), &v) == C.EGL_FALSE {
+		return 0, lastError(fmt.Sprintf("failed to get config attribute %#x", int(attr)))
+	}
+	return int(v), nil
+}
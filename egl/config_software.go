@@ -0,0 +1,164 @@
+//go:build software
+// +build software
+
+package egl
+
+import (
+	"fmt"
+)
+
+// Config wraps an EGLConfig of a Display.
+type Config struct {
+	display *softDisplay
+	id      int
+}
+
+// softConfigs holds the attributes of the configs of the software display,
+// in the order eglChooseConfig would sort them. Config IDs index it from 1.
+var softConfigs = []map[Attrib]int{
+	softConfig(1, 8, 8, 8, 8, 0),
+	softConfig(2, 8, 8, 8, 8, 4),
+	softConfig(3, 5, 6, 5, 0, 0),
+	softConfig(4, 5, 6, 5, 0, 4),
+}
+
+func softConfig(id, red, green, blue, alpha, samples int) map[Attrib]int {
+	sampleBuffers := 0
+	if samples > 0 {
+		sampleBuffers = 1
+	}
+	return map[Attrib]int{
+		BufferSize:        red + green + blue + alpha,
+		RedSize:           red,
+		GreenSize:         green,
+		BlueSize:          blue,
+		AlphaSize:         alpha,
+		LuminanceSize:     0,
+		AlphaMaskSize:     8,
+		DepthSize:         0,
+		StencilSize:       0,
+		Samples:           samples,
+		SampleBuffers:     sampleBuffers,
+		ConfigID:          id,
+		ConfigCaveat:      0x3038, // EGL_NONE
+		ColorBufferType:   0x308E, // EGL_RGB_BUFFER
+		Level:             0,
+		MaxPbufferWidth:   2048,
+		MaxPbufferHeight:  2048,
+		MaxPbufferPixels:  2048 * 2048,
+		NativeRenderable:  0,
+		NativeVisualID:    0,
+		NativeVisualType:  0x3038, // EGL_NONE
+		SurfaceType:       int(WindowBit | PbufferBit | PixmapBit | VGColorspaceLinearBit | VGAlphaFormatPreBit),
+		RenderableType:    int(OpenVGBit),
+		Conformant:        int(OpenVGBit),
+		TransparentType:   0x3038, // EGL_NONE
+		TransparentRed:    0,
+		TransparentGreen:  0,
+		TransparentBlue:   0,
+		BindToTextureRGB:  0,
+		BindToTextureRGBA: 0,
+		MinSwapInterval:   0,
+		MaxSwapInterval:   1,
+	}
+}
+
+// Attributes that configs match if they have at least the requested value,
+// or all of the requested bits.
+var (
+	atLeastAttribs = map[Attrib]bool{
+		BufferSize: true, RedSize: true, GreenSize: true, BlueSize: true, AlphaSize: true,
+		LuminanceSize: true, AlphaMaskSize: true, DepthSize: true, StencilSize: true,
+		Samples: true, SampleBuffers: true,
+	}
+	maskAttribs = map[Attrib]bool{SurfaceType: true, RenderableType: true, Conformant: true}
+)
+
+// GetConfigs wraps eglGetConfigs, returning every config of d.
+func (d Display) GetConfigs() ([]Config, error) {
+	if !d.handle.initialized {
+		return nil, newError("failed to get configs", NotInitializedError)
+	}
+	configs := make([]Config, len(softConfigs))
+	for i := range softConfigs {
+		configs[i] = Config{d.handle, i + 1}
+	}
+	return configs, nil
+}
+
+// ChooseConfigs wraps eglChooseConfig, returning every config of d matching
+// attribs in EGL's order of preference. Nil attribs are the same as
+// NewConfigAttribs().
+func (d Display) ChooseConfigs(attribs *ConfigAttribs) ([]Config, error) {
+	if !d.handle.initialized {
+		return nil, newError("failed to choose configs", NotInitializedError)
+	}
+	// Unlisted attributes take their EGL defaults, which only restrict these.
+	want := map[Attrib]int{SurfaceType: int(WindowBit), RenderableType: int(OpenGLESBit)}
+	l := attribs.list()
+	for i := 0; Attrib(l[i]) != attribNone; i += 2 {
+		attr := Attrib(l[i])
+		if _, ok := softConfigs[0][attr]; !ok {
+			return nil, newError("failed to choose configs", BadAttributeError)
+		}
+		want[attr] = l[i+1]
+	}
+	var configs []Config
+	for i, c := range softConfigs {
+		if matchConfig(c, want) {
+			configs = append(configs, Config{d.handle, i + 1})
+		}
+	}
+	return configs, nil
+}
+
+func matchConfig(c map[Attrib]int, want map[Attrib]int) bool {
+	for attr, v := range want {
+		switch {
+		case v == DontCare:
+		case atLeastAttribs[attr]:
+			if c[attr] < v {
+				return false
+			}
+		case maskAttribs[attr]:
+			if c[attr]&v != v {
+				return false
+			}
+		case c[attr] != v:
+			return false
+		}
+	}
+	return true
+}
+
+func (c Config) valid() bool {
+	return c.id >= 1 && c.id <= len(softConfigs)
+}
+
+// ChooseConfig returns the config EGL prefers among those matching
+// DefaultConfigAttribs.
+func (d Display) ChooseConfig() (Config, error) {
+	configs, err := d.ChooseConfigs(DefaultConfigAttribs())
+	if err != nil {
+		return Config{}, err
+	}
+	if len(configs) == 0 {
+		return Config{}, ErrNoMatchingConfig
+	}
+	return configs[0], nil
+}
+
+// Attrib wraps eglGetConfigAttrib.
+func (c Config) Attrib(attr Attrib) (int, error) {
+	if c.display == nil || !c.display.initialized {
+		return 0, newError(fmt.Sprintf("failed to get config attribute %#x", int(attr)), NotInitializedError)
+	}
+	if !c.valid() {
+		return 0, newError(fmt.Sprintf("failed to get config attribute %#x", int(attr)), BadConfigError)
+	}
+	v, ok := softConfigs[c.id-1][attr]
+	if !ok {
+		return 0, newError(fmt.Sprintf("failed to get config attribute %#x", int(attr)), BadAttributeError)
+	}
+	return v, nil
+}
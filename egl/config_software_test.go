@@ -0,0 +1,92 @@
+//go:build software
+// +build software
+
+package egl
+
+import "testing"
+
+// initTestDisplay returns the initialized default display, which is
+// terminated when the test ends.
+func initTestDisplay(t *testing.T) Display {
+	t.Helper()
+	d, err := GetDisplay(DefaultDisplay)
+	if err != nil {
+		t.Fatalf("GetDisplay: %v", err)
+	}
+	if _, err := d.Initialize(); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	t.Cleanup(func() { d.Terminate() })
+	return d
+}
+
+func configSamples(t *testing.T, configs []Config) []int {
+	t.Helper()
+	samples := make([]int, len(configs))
+	for i, c := range configs {
+		n, err := c.Attrib(Samples)
+		if err != nil {
+			t.Fatalf("Attrib(Samples): %v", err)
+		}
+		samples[i] = n
+	}
+	return samples
+}
+
+func TestChooseConfigsSamples(t *testing.T) {
+	d := initTestDisplay(t)
+	for _, test := range []struct {
+		samples int
+		want    []int
+	}{
+		{0, []int{0, 4, 0, 4}},
+		{1, []int{0, 4, 0, 4}},
+		{4, []int{4, 4}},
+		{8, []int{}},
+	} {
+		configs, err := d.ChooseConfigs(NewConfigAttribs().RenderableType(OpenVGBit).Samples(test.samples))
+		if err != nil {
+			t.Fatalf("ChooseConfigs(Samples(%d)): %v", test.samples, err)
+		}
+		got := configSamples(t, configs)
+		if len(got) != len(test.want) {
+			t.Errorf("ChooseConfigs(Samples(%d)) matched configs with %v samples, want %v", test.samples, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("ChooseConfigs(Samples(%d)) matched configs with %v samples, want %v", test.samples, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestChooseConfigsNil(t *testing.T) {
+	d := initTestDisplay(t)
+	// EGL's defaults ask for an OpenGL ES window surface, which the software
+	// display does not support.
+	configs, err := d.ChooseConfigs(nil)
+	if err != nil {
+		t.Fatalf("ChooseConfigs(nil): %v", err)
+	}
+	if len(configs) != 0 {
+		t.Errorf("ChooseConfigs(nil) matched %d configs, want 0", len(configs))
+	}
+}
+
+func TestRankPreferSamples(t *testing.T) {
+	d := initTestDisplay(t)
+	configs, err := d.ChooseConfigs(DefaultConfigAttribs())
+	if err != nil {
+		t.Fatalf("ChooseConfigs: %v", err)
+	}
+	Rank(configs, PreferSamples(4))
+	if got := configSamples(t, configs); got[0] != 4 {
+		t.Errorf("after ranking by PreferSamples(4), configs have %v samples", got)
+	}
+	Rank(configs, PreferSamples(1))
+	if got := configSamples(t, configs); got[0] != 0 {
+		t.Errorf("after ranking by PreferSamples(1), configs have %v samples", got)
+	}
+}
@@ -0,0 +1,193 @@
+package egl
+
+import "sort"
+
+// Attrib represents an EGL config attribute.
+type Attrib int
+
+// Config attributes, from EGL 1.4.
+const (
+	BufferSize        = Attrib(0x3020)
+	AlphaSize         = Attrib(0x3021)
+	BlueSize          = Attrib(0x3022)
+	GreenSize         = Attrib(0x3023)
+	RedSize           = Attrib(0x3024)
+	DepthSize         = Attrib(0x3025)
+	StencilSize       = Attrib(0x3026)
+	ConfigCaveat      = Attrib(0x3027)
+	ConfigID          = Attrib(0x3028)
+	Level             = Attrib(0x3029)
+	MaxPbufferHeight  = Attrib(0x302A)
+	MaxPbufferPixels  = Attrib(0x302B)
+	MaxPbufferWidth   = Attrib(0x302C)
+	NativeRenderable  = Attrib(0x302D)
+	NativeVisualID    = Attrib(0x302E)
+	NativeVisualType  = Attrib(0x302F)
+	Samples           = Attrib(0x3031)
+	SampleBuffers     = Attrib(0x3032)
+	SurfaceType       = Attrib(0x3033)
+	TransparentType   = Attrib(0x3034)
+	TransparentBlue   = Attrib(0x3035)
+	TransparentGreen  = Attrib(0x3036)
+	TransparentRed    = Attrib(0x3037)
+	BindToTextureRGB  = Attrib(0x3039)
+	BindToTextureRGBA = Attrib(0x303A)
+	MinSwapInterval   = Attrib(0x303B)
+	MaxSwapInterval   = Attrib(0x303C)
+	LuminanceSize     = Attrib(0x303D)
+	AlphaMaskSize     = Attrib(0x303E)
+	ColorBufferType   = Attrib(0x303F)
+	RenderableType    = Attrib(0x3040)
+	MatchNativePixmap = Attrib(0x3041)
+	Conformant        = Attrib(0x3042)
+
+	attribNone = Attrib(0x3038)
+)
+
+// DontCare matches any value of an attribute.
+const DontCare = -1
+
+// SurfaceTypeBits are the bits of the SurfaceType attribute.
+type SurfaceTypeBits int
+
+const (
+	PbufferBit               = SurfaceTypeBits(0x0001)
+	PixmapBit                = SurfaceTypeBits(0x0002)
+	WindowBit                = SurfaceTypeBits(0x0004)
+	VGColorspaceLinearBit    = SurfaceTypeBits(0x0020)
+	VGAlphaFormatPreBit      = SurfaceTypeBits(0x0040)
+	MultisampleResolveBoxBit = SurfaceTypeBits(0x0200)
+	SwapBehaviorPreservedBit = SurfaceTypeBits(0x0400)
+)
+
+// RenderableTypeBits are the bits of the RenderableType and Conformant
+// attributes.
+type RenderableTypeBits int
+
+const (
+	OpenGLESBit  = RenderableTypeBits(0x0001)
+	OpenVGBit    = RenderableTypeBits(0x0002)
+	OpenGLES2Bit = RenderableTypeBits(0x0004)
+	OpenGLBit    = RenderableTypeBits(0x0008)
+)
+
+// ConfigAttribs builds the attribute list passed to ChooseConfigs. Each
+// setter replaces any earlier value of its attributes and returns a for
+// chaining, e.g.
+//
+//	egl.NewConfigAttribs().RGBA(8, 8, 8, 8).Samples(4)
+type ConfigAttribs struct {
+	attribs []Attrib
+	values  map[Attrib]int
+}
+
+// NewConfigAttribs returns an empty attribute list, which EGL completes with
+// its defaults.
+func NewConfigAttribs() *ConfigAttribs {
+	return &ConfigAttribs{values: map[Attrib]int{}}
+}
+
+// DefaultConfigAttribs returns the attributes used by ChooseConfig: 8 bits per
+// channel of a window surface that OpenVG can render to.
+func DefaultConfigAttribs() *ConfigAttribs {
+	return NewConfigAttribs().
+		RGBA(8, 8, 8, 8).
+		SurfaceType(WindowBit).
+		RenderableType(OpenVGBit)
+}
+
+// Set sets attribute attr to value, which may be DontCare.
+func (a *ConfigAttribs) Set(attr Attrib, value int) *ConfigAttribs {
+	if _, ok := a.values[attr]; !ok {
+		a.attribs = append(a.attribs, attr)
+	}
+	a.values[attr] = value
+	return a
+}
+
+// RGBA sets the minimum sizes of the color channels in bits.
+func (a *ConfigAttribs) RGBA(red, green, blue, alpha int) *ConfigAttribs {
+	return a.Set(RedSize, red).Set(GreenSize, green).Set(BlueSize, blue).Set(AlphaSize, alpha)
+}
+
+// SurfaceType sets the kinds of surface the config must support.
+func (a *ConfigAttribs) SurfaceType(bits SurfaceTypeBits) *ConfigAttribs {
+	return a.Set(SurfaceType, int(bits))
+}
+
+// RenderableType sets the client APIs the config must support.
+func (a *ConfigAttribs) RenderableType(bits RenderableTypeBits) *ConfigAttribs {
+	return a.Set(RenderableType, int(bits))
+}
+
+// Samples sets the minimum number of samples per pixel. More than one sample
+// requires a multisample buffer. Configs without one report 0 samples, so n of
+// 1 or less sets no minimum.
+func (a *ConfigAttribs) Samples(n int) *ConfigAttribs {
+	if n <= 1 {
+		return a.Set(SampleBuffers, 0).Set(Samples, 0)
+	}
+	return a.Set(SampleBuffers, 1).Set(Samples, n)
+}
+
+// DepthSize sets the minimum size of the depth buffer in bits.
+func (a *ConfigAttribs) DepthSize(bits int) *ConfigAttribs {
+	return a.Set(DepthSize, bits)
+}
+
+// StencilSize sets the minimum size of the stencil buffer in bits.
+func (a *ConfigAttribs) StencilSize(bits int) *ConfigAttribs {
+	return a.Set(StencilSize, bits)
+}
+
+// AlphaMaskSize sets the minimum size of the alpha mask buffer, which backs
+// the OpenVG mask, in bits.
+func (a *ConfigAttribs) AlphaMaskSize(bits int) *ConfigAttribs {
+	return a.Set(AlphaMaskSize, bits)
+}
+
+// list returns the attributes and values in the order they were first set,
+// terminated by EGL_NONE. A nil a is empty.
+func (a *ConfigAttribs) list() []int {
+	if a == nil {
+		return []int{int(attribNone)}
+	}
+	l := make([]int, 0, 2*len(a.attribs)+1)
+	for _, attr := range a.attribs {
+		l = append(l, int(attr), a.values[attr])
+	}
+	return append(l, int(attribNone))
+}
+
+// Rank sorts configs in place from the highest score to the lowest. Configs
+// with equal scores keep their order, which for configs returned by
+// ChooseConfigs is EGL's own preference.
+func Rank(configs []Config, score func(Config) int) {
+	scores := make(map[Config]int, len(configs))
+	for _, c := range configs {
+		scores[c] = score(c)
+	}
+	sort.SliceStable(configs, func(i, j int) bool {
+		return scores[configs[i]] > scores[configs[j]]
+	})
+}
+
+// PreferSamples returns a score for Rank that favors configs with n samples
+// per pixel, then those with the most samples below n, and finally those with
+// more than n, fewest first. Ranking by PreferSamples(4) picks a 4x MSAA config
+// where available and the best alternative otherwise.
+func PreferSamples(n int) func(Config) int {
+	return func(c Config) int {
+		samples, err := c.Attrib(Samples)
+		switch {
+		case err != nil:
+			return -1 << 30
+		case samples == n:
+			return 1 << 30
+		case samples < n:
+			return samples
+		default:
+			return -samples
+		}
+	}
+}
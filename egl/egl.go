@@ -1,3 +1,6 @@
+//go:build !software
+// +build !software
+
 package egl
 
 /*
@@ -7,9 +10,7 @@ package egl
 */
 import "C"
 import (
-	"errors"
 	"fmt"
-	"log"
 	"unsafe"
 )
 
@@ -31,13 +32,10 @@ type NativeWindow interface {
 	Handle() NativeWindowHandle
 }
 
-type Config struct {
-	handle C.EGLConfig
-}
-
-var errNames = map[C.EGLint]string{
-	C.EGL_NOT_INITIALIZED: "EGL_NOT_INITIALIZED",
-	C.EGL_BAD_DISPLAY:     "EGL_BAD_DISPLAY",
+// lastError returns the error reported by eglGetError for the failed
+// operation op.
+func lastError(op string) error {
+	return newError(op, ErrorCode(C.eglGetError()))
 }
 
 type Display struct {
@@ -47,12 +45,8 @@ type Display struct {
 func GetDisplay(display NativeDisplayType) (Display, error) {
 	handle := C.eglGetDisplay(C.EGLNativeDisplayType(display))
 	if handle == C.EGLDisplay(C.EGL_NO_DISPLAY) {
-		return Display{}, fmt.Errorf("could not get display for \"%d\"", display)
-	}
-	major := C.EGLint(0)
-	minor := C.EGLint(0)
-	if C.eglInitialize(handle, &major, &minor) == C.EGL_FALSE {
-		log.Printf("xxxfailed to initialize display: %s", errNames[C.eglGetError()])
+		// eglGetDisplay does not set an error.
+		return Display{}, newError(fmt.Sprintf("could not get display for \"%d\"", display), BadDisplayError)
 	}
 	return Display{handle}, nil
 }
@@ -61,43 +55,22 @@ func (d Display) Initialize() (string, error) {
 	major := C.EGLint(0)
 	minor := C.EGLint(0)
 	if C.eglInitialize(d.handle, &major, &minor) == C.EGL_FALSE {
-		return "", fmt.Errorf("failed to initialize display: %s", errNames[C.eglGetError()])
+		return "", lastError("failed to initialize display")
 	}
 	return fmt.Sprintf("%d.%d", major, minor), nil
 }
 
 func (d Display) Terminate() error {
 	if C.eglTerminate(d.handle) == C.EGL_FALSE {
-		return errors.New("failed to terminate display")
+		return lastError("failed to terminate display")
 	}
 	return nil
 }
 
-func (d Display) ChooseConfig() (Config, error) {
-	attribs := []C.EGLint{
-		C.EGL_RED_SIZE, 8,
-		C.EGL_GREEN_SIZE, 8,
-		C.EGL_BLUE_SIZE, 8,
-		C.EGL_ALPHA_SIZE, 8,
-		C.EGL_LUMINANCE_SIZE, C.EGL_DONT_CARE,
-		C.EGL_SAMPLES, 1,
-		C.EGL_NONE,
-	}
-	cfg := C.EGLConfig(unsafe.Pointer(uintptr(0)))
-	var numConfigs C.EGLint
-	if C.eglChooseConfig(d.handle, &attribs[0], &cfg, 1 /*size*/, &numConfigs) == C.EGL_FALSE {
-		return Config{}, fmt.Errorf("failed to choose configs: %s", errNames[C.eglGetError()])
-	}
-	if numConfigs != 1 {
-		return Config{}, fmt.Errorf("wanted 1 matching config, got %d", numConfigs)
-	}
-	return Config{cfg}, nil
-}
-
 func (d Display) CreateWindowSurface(config Config, window NativeWindow) (Surface, error) {
 	handle := C.eglCreateWindowSurface(d.handle, config.handle, C.EGLNativeWindowType(window.Handle()), (*C.EGLint)(unsafe.Pointer(uintptr(0))))
 	if handle == C.EGLSurface(C.EGL_NO_SURFACE) {
-		return Surface{}, fmt.Errorf("failed creating window surface: %v", errNames[C.eglGetError()])
+		return Surface{}, lastError("failed creating window surface")
 	}
 	return Surface{handle}, nil
 }
@@ -105,7 +78,7 @@ func (d Display) CreateWindowSurface(config Config, window NativeWindow) (Surfac
 func (d Display) CreateContext(config Config) (Context, error) {
 	ctx := C.eglCreateContext(d.handle, config.handle, C.EGLContext(unsafe.Pointer(uintptr(0))), (*C.EGLint)(unsafe.Pointer(uintptr(0))))
 	if ctx == C.EGLContext(C.EGL_NO_CONTEXT) {
-		return Context{}, errors.New("failed to create context")
+		return Context{}, lastError("failed to create context")
 	}
 	return Context{ctx}, nil
 }
@@ -116,14 +89,14 @@ type Context struct {
 
 func (d Display) MakeCurrent(surface Surface, ctx Context) error {
 	if C.eglMakeCurrent(d.handle, surface.handle, surface.handle, ctx.handle) == C.EGL_FALSE {
-		return fmt.Errorf("failed to make context current: %s", errNames[C.eglGetError()])
+		return lastError("failed to make context current")
 	}
 	return nil
 }
 
 func (d Display) SwapBuffers(surface Surface) error {
 	if C.eglSwapBuffers(d.handle, surface.handle) == C.EGL_FALSE {
-		return fmt.Errorf("failed to swap buffers: %s", errNames[C.eglGetError()])
+		return lastError("failed to swap buffers")
 	}
 	return nil
 }
@@ -134,7 +107,81 @@ type Surface struct {
 
 func BindAPI(api Api) error {
 	if C.eglBindAPI(C.uint(api)) == C.EGL_FALSE {
-		return fmt.Errorf("could not bind API: %s", errNames[C.eglGetError()])
+		return lastError("could not bind API")
+	}
+	return nil
+}
+
+// ReleaseCurrent wraps eglMakeCurrent with no surfaces and no context,
+// releasing the context current on the calling thread.
+func (d Display) ReleaseCurrent() error {
+	if C.eglMakeCurrent(d.handle, C.EGLSurface(C.EGL_NO_SURFACE), C.EGLSurface(C.EGL_NO_SURFACE), C.EGLContext(C.EGL_NO_CONTEXT)) == C.EGL_FALSE {
+		return lastError("failed to release context")
+	}
+	return nil
+}
+
+// DestroyContext wraps eglDestroyContext. A context that is current is only
+// destroyed once it is released.
+func (d Display) DestroyContext(ctx Context) error {
+	if C.eglDestroyContext(d.handle, ctx.handle) == C.EGL_FALSE {
+		return lastError("failed to destroy context")
+	}
+	return nil
+}
+
+// DestroySurface wraps eglDestroySurface. A surface that is current is only
+// destroyed once it is released.
+func (d Display) DestroySurface(surface Surface) error {
+	if C.eglDestroySurface(d.handle, surface.handle) == C.EGL_FALSE {
+		return lastError("failed to destroy surface")
+	}
+	return nil
+}
+
+// ReleaseThread wraps eglReleaseThread, releasing the context current on the
+// calling thread and resetting the bound API.
+func ReleaseThread() error {
+	if C.eglReleaseThread() == C.EGL_FALSE {
+		return lastError("failed to release thread")
+	}
+	return nil
+}
+
+// QuerySurface wraps eglQuerySurface.
+func (d Display) QuerySurface(surface Surface, attr Attrib) (int, error) {
+	var v C.EGLint
+	if C.eglQuerySurface(d.handle, surface.handle, C.EGLint(attr), &v) == C.EGL_FALSE {
+		return 0, lastError(fmt.Sprintf("failed to query surface attribute %#x", int(attr)))
+	}
+	return int(v), nil
+}
+
+// QueryContext wraps eglQueryContext.
+func (d Display) QueryContext(ctx Context, attr Attrib) (int, error) {
+	var v C.EGLint
+	if C.eglQueryContext(d.handle, ctx.handle, C.EGLint(attr), &v) == C.EGL_FALSE {
+		return 0, lastError(fmt.Sprintf("failed to query context attribute %#x", int(attr)))
+	}
+	return int(v), nil
+}
+
+// SwapInterval wraps eglSwapInterval, setting the minimum number of video
+// frames between buffer swaps of the surface current on the calling thread: 0
+// disables vsync and 1 swaps once per frame. The interval is clamped to the
+// MinSwapInterval and MaxSwapInterval of the surface's config.
+func (d Display) SwapInterval(interval int) error {
+	if C.eglSwapInterval(d.handle, C.EGLint(interval)) == C.EGL_FALSE {
+		return lastError("failed to set swap interval")
 	}
 	return nil
 }
+
+// QueryString wraps eglQueryString.
+func (d Display) QueryString(name StringName) (string, error) {
+	s := C.eglQueryString(d.handle, C.EGLint(name))
+	if s == nil {
+		return "", lastError(fmt.Sprintf("failed to query string %#x", int(name)))
+	}
+	return C.GoString(s), nil
+}
@@ -0,0 +1,312 @@
+//go:build software
+// +build software
+
+package egl
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"../openvg"
+)
+
+// The software backend stands in for EGL so that the openvg software backend
+// can be driven by the same setup code as on the device. Window surfaces are
+// in-memory images; SwapBuffers publishes the back buffer as the surface's
+// Frame.
+
+type NativeDisplayType int
+
+var (
+	DefaultDisplay = NativeDisplayType(0)
+)
+
+type Api uint
+
+const (
+	APIOpenVG Api = 0x30A1
+)
+
+// NativeWindowHandle describes the window a surface is created for.
+type NativeWindowHandle struct {
+	Width, Height int
+}
+
+type NativeWindow interface {
+	Handle() NativeWindowHandle
+}
+
+type Display struct {
+	handle *softDisplay
+}
+
+type softDisplay struct {
+	initialized bool
+}
+
+var boundAPI Api
+
+func GetDisplay(display NativeDisplayType) (Display, error) {
+	if display != DefaultDisplay {
+		return Display{}, newError(fmt.Sprintf("could not get display for \"%d\"", display), BadDisplayError)
+	}
+	return Display{&softDisplay{}}, nil
+}
+
+func (d Display) Initialize() (string, error) {
+	d.handle.initialized = true
+	return "1.4", nil
+}
+
+func (d Display) Terminate() error {
+	if !d.handle.initialized {
+		return newError("failed to terminate display", NotInitializedError)
+	}
+	d.handle.initialized = false
+	openvg.BindSurface(nil)
+	return nil
+}
+
+func (d Display) CreateWindowSurface(config Config, window NativeWindow) (Surface, error) {
+	h := window.Handle()
+	if !d.handle.initialized {
+		return Surface{}, newError("failed creating window surface", NotInitializedError)
+	}
+	if !config.valid() {
+		return Surface{}, newError("failed creating window surface", BadConfigError)
+	}
+	if h.Width <= 0 || h.Height <= 0 {
+		return Surface{}, newError("failed creating window surface", BadNativeWindowError)
+	}
+	r := image.Rect(0, 0, h.Width, h.Height)
+	return Surface{&softSurface{
+		back:         image.NewRGBA(r),
+		front:        image.NewRGBA(r),
+		config:       config.id,
+		interval:     1,
+		renderBuffer: BackBuffer,
+	}}, nil
+}
+
+func (d Display) CreateContext(config Config) (Context, error) {
+	if !d.handle.initialized {
+		return Context{}, newError("failed to create context", NotInitializedError)
+	}
+	if !config.valid() {
+		return Context{}, newError("failed to create context", BadConfigError)
+	}
+	if boundAPI != APIOpenVG {
+		return Context{}, newError("failed to create context", BadMatchError)
+	}
+	return Context{&softContext{config: config.id}}, nil
+}
+
+type Context struct {
+	handle *softContext
+}
+
+type softContext struct {
+	config    int
+	destroyed bool
+}
+
+// current holds the surface and context current on the calling thread, which
+// the software backend treats as a single thread.
+var current struct {
+	surface *softSurface
+	context *softContext
+}
+
+func (d Display) MakeCurrent(surface Surface, ctx Context) error {
+	if surface.handle == nil || ctx.handle == nil {
+		return newError("failed to make context current", BadMatchError)
+	}
+	if surface.handle.destroyed {
+		return newError("failed to make context current", BadSurfaceError)
+	}
+	if ctx.handle.destroyed {
+		return newError("failed to make context current", BadContextError)
+	}
+	current.surface, current.context = surface.handle, ctx.handle
+	openvg.BindSurface(surface.handle.back)
+	return nil
+}
+
+func (d Display) SwapBuffers(surface Surface) error {
+	if surface.handle == nil || surface.handle.destroyed {
+		return newError("failed to swap buffers", BadSurfaceError)
+	}
+	s := surface.handle
+	// Swapping has no effect on pbuffer and pixmap surfaces.
+	if s.front != s.back {
+		draw.Draw(s.front, s.front.Bounds(), s.back, image.ZP, draw.Src)
+	}
+	s.frames++
+	return nil
+}
+
+type Surface struct {
+	handle *softSurface
+}
+
+type softSurface struct {
+	// back and front are the same image for pbuffer and pixmap surfaces.
+	back, front  *image.RGBA
+	frames       int
+	config       int
+	interval     int
+	renderBuffer int
+	destroyed    bool
+}
+
+// Frame returns the image published by the last SwapBuffers, in top-down
+// order. It is overwritten by the next SwapBuffers. For pbuffer and pixmap
+// surfaces it is the image drawn to.
+func (s Surface) Frame() *image.RGBA {
+	return s.handle.front
+}
+
+// Frames returns the number of times SwapBuffers was called on s.
+func (s Surface) Frames() int {
+	return s.handle.frames
+}
+
+func BindAPI(api Api) error {
+	if api != APIOpenVG {
+		return newError("could not bind API", BadParameterError)
+	}
+	boundAPI = api
+	return nil
+}
+
+// ReleaseCurrent wraps eglMakeCurrent with no surfaces and no context,
+// releasing the context current on the calling thread.
+func (d Display) ReleaseCurrent() error {
+	if !d.handle.initialized {
+		return newError("failed to release context", NotInitializedError)
+	}
+	releaseCurrent()
+	return nil
+}
+
+func releaseCurrent() {
+	current.surface, current.context = nil, nil
+	openvg.BindSurface(nil)
+}
+
+// DestroyContext wraps eglDestroyContext. A context that is current is only
+// destroyed once it is released.
+func (d Display) DestroyContext(ctx Context) error {
+	if ctx.handle == nil || ctx.handle.destroyed {
+		return newError("failed to destroy context", BadContextError)
+	}
+	ctx.handle.destroyed = true
+	return nil
+}
+
+// DestroySurface wraps eglDestroySurface. A surface that is current is only
+// destroyed once it is released.
+func (d Display) DestroySurface(surface Surface) error {
+	if surface.handle == nil || surface.handle.destroyed {
+		return newError("failed to destroy surface", BadSurfaceError)
+	}
+	surface.handle.destroyed = true
+	return nil
+}
+
+// ReleaseThread wraps eglReleaseThread, releasing the context current on the
+// calling thread and resetting the bound API.
+func ReleaseThread() error {
+	releaseCurrent()
+	boundAPI = 0
+	return nil
+}
+
+// QuerySurface wraps eglQuerySurface.
+func (d Display) QuerySurface(surface Surface, attr Attrib) (int, error) {
+	if surface.handle == nil || surface.handle.destroyed {
+		return 0, newError(fmt.Sprintf("failed to query surface attribute %#x", int(attr)), BadSurfaceError)
+	}
+	s := surface.handle
+	switch attr {
+	case Width:
+		return s.back.Bounds().Dx(), nil
+	case Height:
+		return s.back.Bounds().Dy(), nil
+	case ConfigID:
+		return s.config, nil
+	case RenderBuffer:
+		return s.renderBuffer, nil
+	case LargestPbuffer:
+		// Never requested by CreatePbufferSurface.
+		return 0, nil
+	case SwapBehavior:
+		return BufferDestroyed, nil
+	case VGColorspace:
+		return VGColorspaceSRGB, nil
+	case VGAlphaFormat:
+		return VGAlphaFormatNonpre, nil
+	}
+	return 0, newError(fmt.Sprintf("failed to query surface attribute %#x", int(attr)), BadAttributeError)
+}
+
+// QueryContext wraps eglQueryContext.
+func (d Display) QueryContext(ctx Context, attr Attrib) (int, error) {
+	if ctx.handle == nil || ctx.handle.destroyed {
+		return 0, newError(fmt.Sprintf("failed to query context attribute %#x", int(attr)), BadContextError)
+	}
+	switch attr {
+	case ConfigID:
+		return ctx.handle.config, nil
+	case ContextClientType:
+		return int(APIOpenVG), nil
+	case RenderBuffer:
+		if current.context == ctx.handle && current.surface != nil {
+			return BackBuffer, nil
+		}
+		return int(attribNone), nil
+	}
+	return 0, newError(fmt.Sprintf("failed to query context attribute %#x", int(attr)), BadAttributeError)
+}
+
+// SwapInterval wraps eglSwapInterval, setting the minimum number of video
+// frames between buffer swaps of the surface current on the calling thread: 0
+// disables vsync and 1 swaps once per frame. The interval is clamped to the
+// MinSwapInterval and MaxSwapInterval of the surface's config. The software
+// backend records it but never waits.
+func (d Display) SwapInterval(interval int) error {
+	if current.context == nil {
+		return newError("failed to set swap interval", BadContextError)
+	}
+	if current.surface == nil {
+		return newError("failed to set swap interval", BadSurfaceError)
+	}
+	c := softConfigs[current.surface.config-1]
+	if interval < c[MinSwapInterval] {
+		interval = c[MinSwapInterval]
+	}
+	if interval > c[MaxSwapInterval] {
+		interval = c[MaxSwapInterval]
+	}
+	current.surface.interval = interval
+	return nil
+}
+
+// QueryString wraps eglQueryString.
+func (d Display) QueryString(name StringName) (string, error) {
+	if !d.handle.initialized {
+		return "", newError(fmt.Sprintf("failed to query string %#x", int(name)), NotInitializedError)
+	}
+	switch name {
+	case Vendor:
+		return "webcam-openvg-demo software", nil
+	case Version:
+		return "1.4 software", nil
+	case Extensions:
+		return "", nil
+	case ClientAPIs:
+		return "OpenVG", nil
+	}
+	return "", newError(fmt.Sprintf("failed to query string %#x", int(name)), BadParameterError)
+}
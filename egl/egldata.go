@@ -0,0 +1,41 @@
+package egl
+
+// Surface and context attributes for QuerySurface and QueryContext, from EGL
+// 1.4. ConfigID can be queried for both.
+const (
+	Height               = Attrib(0x3056)
+	Width                = Attrib(0x3057)
+	LargestPbuffer       = Attrib(0x3058)
+	RenderBuffer         = Attrib(0x3086)
+	VGColorspace         = Attrib(0x3087)
+	VGAlphaFormat        = Attrib(0x3088)
+	HorizontalResolution = Attrib(0x3090)
+	VerticalResolution   = Attrib(0x3091)
+	PixelAspectRatio     = Attrib(0x3092)
+	SwapBehavior         = Attrib(0x3093)
+	ContextClientType    = Attrib(0x3097)
+	ContextClientVersion = Attrib(0x3098)
+	MultisampleResolve   = Attrib(0x3099)
+)
+
+// Values of surface and context attributes.
+const (
+	BackBuffer          = 0x3084
+	SingleBuffer        = 0x3085
+	VGColorspaceSRGB    = 0x3089
+	VGColorspaceLinear  = 0x308A
+	VGAlphaFormatNonpre = 0x308B
+	VGAlphaFormatPre    = 0x308C
+	BufferPreserved     = 0x3094
+	BufferDestroyed     = 0x3095
+)
+
+// StringName names a string returned by QueryString.
+type StringName int
+
+const (
+	Vendor     = StringName(0x3053)
+	Version    = StringName(0x3054)
+	Extensions = StringName(0x3055)
+	ClientAPIs = StringName(0x308D)
+)
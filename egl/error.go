@@ -0,0 +1,101 @@
+package egl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode represents an error code returned by eglGetError.
+type ErrorCode int
+
+// Values returned by eglGetError, from EGL 1.4.
+const (
+	Success                = ErrorCode(0x3000)
+	NotInitializedError    = ErrorCode(0x3001)
+	BadAccessError         = ErrorCode(0x3002)
+	BadAllocError          = ErrorCode(0x3003)
+	BadAttributeError      = ErrorCode(0x3004)
+	BadConfigError         = ErrorCode(0x3005)
+	BadContextError        = ErrorCode(0x3006)
+	BadCurrentSurfaceError = ErrorCode(0x3007)
+	BadDisplayError        = ErrorCode(0x3008)
+	BadMatchError          = ErrorCode(0x3009)
+	BadNativePixmapError   = ErrorCode(0x300A)
+	BadNativeWindowError   = ErrorCode(0x300B)
+	BadParameterError      = ErrorCode(0x300C)
+	BadSurfaceError        = ErrorCode(0x300D)
+	ContextLostError       = ErrorCode(0x300E)
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	Success:                "EGL_SUCCESS",
+	NotInitializedError:    "EGL_NOT_INITIALIZED",
+	BadAccessError:         "EGL_BAD_ACCESS",
+	BadAllocError:          "EGL_BAD_ALLOC",
+	BadAttributeError:      "EGL_BAD_ATTRIBUTE",
+	BadConfigError:         "EGL_BAD_CONFIG",
+	BadContextError:        "EGL_BAD_CONTEXT",
+	BadCurrentSurfaceError: "EGL_BAD_CURRENT_SURFACE",
+	BadDisplayError:        "EGL_BAD_DISPLAY",
+	BadMatchError:          "EGL_BAD_MATCH",
+	BadNativePixmapError:   "EGL_BAD_NATIVE_PIXMAP",
+	BadNativeWindowError:   "EGL_BAD_NATIVE_WINDOW",
+	BadParameterError:      "EGL_BAD_PARAMETER",
+	BadSurfaceError:        "EGL_BAD_SURFACE",
+	ContextLostError:       "EGL_CONTEXT_LOST",
+}
+
+func (c ErrorCode) String() string {
+	if name, ok := errorCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("EGLError(%#x)", int(c))
+}
+
+// Error wraps an error code returned by eglGetError. Two Errors match with
+// errors.Is when their codes are equal, so a returned Error can be compared
+// against the sentinel values below.
+type Error struct {
+	Code ErrorCode
+	// Op describes the failed operation, e.g. "failed to swap buffers".
+	Op string
+}
+
+// Sentinel errors for each error code.
+var (
+	ErrNotInitialized    = &Error{Code: NotInitializedError}
+	ErrBadAccess         = &Error{Code: BadAccessError}
+	ErrBadAlloc          = &Error{Code: BadAllocError}
+	ErrBadAttribute      = &Error{Code: BadAttributeError}
+	ErrBadConfig         = &Error{Code: BadConfigError}
+	ErrBadContext        = &Error{Code: BadContextError}
+	ErrBadCurrentSurface = &Error{Code: BadCurrentSurfaceError}
+	ErrBadDisplay        = &Error{Code: BadDisplayError}
+	ErrBadMatch          = &Error{Code: BadMatchError}
+	ErrBadNativePixmap   = &Error{Code: BadNativePixmapError}
+	ErrBadNativeWindow   = &Error{Code: BadNativeWindowError}
+	ErrBadParameter      = &Error{Code: BadParameterError}
+	ErrBadSurface        = &Error{Code: BadSurfaceError}
+	ErrContextLost       = &Error{Code: ContextLostError}
+)
+
+// ErrNoMatchingConfig is returned by ChooseConfig when no config matches.
+var ErrNoMatchingConfig = errors.New("failed to choose configs: no matching config")
+
+// newError returns an Error describing the failed operation op.
+func newError(op string, code ErrorCode) *Error {
+	return &Error{Code: code, Op: op}
+}
+
+func (e *Error) Error() string {
+	if e.Op == "" {
+		return e.Code.String()
+	}
+	return e.Op + ": " + e.Code.String()
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
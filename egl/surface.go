@@ -0,0 +1,67 @@
+//go:build !software
+// +build !software
+
+package egl
+
+/*
+  #include "EGL/egl.h"
+  #include <stdint.h>
+
+  static EGLClientBuffer vgImageBuffer(uintptr_t image) {
+    return (EGLClientBuffer)image;
+  }
+*/
+import "C"
+import "../openvg"
+
+type NativePixmapHandle C.EGLNativePixmapType
+
+// NativePixmap is an image of the native platform that a surface can be
+// created for.
+type NativePixmap interface {
+	PixmapHandle() NativePixmapHandle
+}
+
+// CreatePbufferSurface wraps eglCreatePbufferSurface, creating an offscreen
+// surface of width x height pixels. Drawing is read back with
+// openvg.ReadPixels while the surface is current.
+func (d Display) CreatePbufferSurface(config Config, width, height int) (Surface, error) {
+	attribs := []C.EGLint{
+		C.EGL_WIDTH, C.EGLint(width),
+		C.EGL_HEIGHT, C.EGLint(height),
+		C.EGL_NONE,
+	}
+	handle := C.eglCreatePbufferSurface(d.handle, config.handle, &attribs[0])
+	if handle == C.EGLSurface(C.EGL_NO_SURFACE) {
+		return Surface{}, lastError("failed creating pbuffer surface")
+	}
+	return Surface{handle}, nil
+}
+
+// CreatePbufferFromClientBuffer wraps eglCreatePbufferFromClientBuffer with
+// EGL_OPENVG_IMAGE, creating a surface that draws into img. The color channel
+// sizes of config must match the format of img, or EGL returns BadMatchError.
+// While the surface is current, img must not be used by OpenVG calls; its
+// contents can be read once the surface is released.
+func (d Display) CreatePbufferFromClientBuffer(config Config, img openvg.Image) (Surface, error) {
+	handle := C.eglCreatePbufferFromClientBuffer(
+		d.handle,
+		C.EGL_OPENVG_IMAGE,
+		C.vgImageBuffer(C.uintptr_t(img.ClientBuffer())),
+		config.handle,
+		nil /*attrib_list*/)
+	if handle == C.EGLSurface(C.EGL_NO_SURFACE) {
+		return Surface{}, lastError("failed creating pbuffer surface")
+	}
+	return Surface{handle}, nil
+}
+
+// CreatePixmapSurface wraps eglCreatePixmapSurface, creating a surface that
+// draws into pixmap.
+func (d Display) CreatePixmapSurface(config Config, pixmap NativePixmap) (Surface, error) {
+	handle := C.eglCreatePixmapSurface(d.handle, config.handle, C.EGLNativePixmapType(pixmap.PixmapHandle()), nil /*attrib_list*/)
+	if handle == C.EGLSurface(C.EGL_NO_SURFACE) {
+		return Surface{}, lastError("failed creating pixmap surface")
+	}
+	return Surface{handle}, nil
+}
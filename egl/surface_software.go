@@ -0,0 +1,97 @@
+//go:build software
+// +build software
+
+package egl
+
+import (
+	"image"
+
+	"../openvg"
+)
+
+// NativePixmapHandle describes the image a pixmap surface draws into.
+type NativePixmapHandle struct {
+	Image *image.RGBA
+}
+
+// NativePixmap is an image of the native platform that a surface can be
+// created for.
+type NativePixmap interface {
+	PixmapHandle() NativePixmapHandle
+}
+
+// CreatePbufferSurface wraps eglCreatePbufferSurface, creating an offscreen
+// surface of width x height pixels. Drawing is read back with
+// openvg.ReadPixels while the surface is current.
+func (d Display) CreatePbufferSurface(config Config, width, height int) (Surface, error) {
+	if err := d.checkSurfaceConfig("pbuffer", config, PbufferBit); err != nil {
+		return Surface{}, err
+	}
+	if width < 0 || height < 0 {
+		return Surface{}, newError("failed creating pbuffer surface", BadParameterError)
+	}
+	c := softConfigs[config.id-1]
+	if width > c[MaxPbufferWidth] || height > c[MaxPbufferHeight] {
+		return Surface{}, newError("failed creating pbuffer surface", BadMatchError)
+	}
+	return offscreenSurface(config, image.NewRGBA(image.Rect(0, 0, width, height)), BackBuffer), nil
+}
+
+// CreatePbufferFromClientBuffer wraps eglCreatePbufferFromClientBuffer with
+// EGL_OPENVG_IMAGE, creating a surface that draws into img. The color channel
+// sizes of config must match the format of img, or it returns BadMatchError.
+// While the surface is current, img must not be used by OpenVG calls; its
+// contents can be read once the surface is released.
+func (d Display) CreatePbufferFromClientBuffer(config Config, img openvg.Image) (Surface, error) {
+	if err := d.checkSurfaceConfig("pbuffer", config, PbufferBit); err != nil {
+		return Surface{}, err
+	}
+	pix := img.ClientBuffer().Image
+	if pix == nil {
+		return Surface{}, newError("failed creating pbuffer surface", BadParameterError)
+	}
+	red, green, blue, alpha, luminance := img.Format().ChannelSizes()
+	c := softConfigs[config.id-1]
+	if c[RedSize] != red || c[GreenSize] != green || c[BlueSize] != blue ||
+		c[AlphaSize] != alpha || c[LuminanceSize] != luminance {
+		return Surface{}, newError("failed creating pbuffer surface", BadMatchError)
+	}
+	return offscreenSurface(config, pix, BackBuffer), nil
+}
+
+// CreatePixmapSurface wraps eglCreatePixmapSurface, creating a surface that
+// draws into pixmap.
+func (d Display) CreatePixmapSurface(config Config, pixmap NativePixmap) (Surface, error) {
+	if err := d.checkSurfaceConfig("pixmap", config, PixmapBit); err != nil {
+		return Surface{}, err
+	}
+	pix := pixmap.PixmapHandle().Image
+	if pix == nil {
+		return Surface{}, newError("failed creating pixmap surface", BadNativePixmapError)
+	}
+	return offscreenSurface(config, pix, SingleBuffer), nil
+}
+
+func (d Display) checkSurfaceConfig(kind string, config Config, bit SurfaceTypeBits) error {
+	if !d.handle.initialized {
+		return newError("failed creating "+kind+" surface", NotInitializedError)
+	}
+	if !config.valid() {
+		return newError("failed creating "+kind+" surface", BadConfigError)
+	}
+	if SurfaceTypeBits(softConfigs[config.id-1][SurfaceType])&bit == 0 {
+		return newError("failed creating "+kind+" surface", BadMatchError)
+	}
+	return nil
+}
+
+// offscreenSurface returns a surface that draws into pix.
+func offscreenSurface(config Config, pix *image.RGBA, renderBuffer int) Surface {
+	return Surface{&softSurface{
+		back:         pix,
+		front:        pix,
+		config:       config.id,
+		interval:     1,
+		renderBuffer: renderBuffer,
+	}}
+}
@@ -0,0 +1,54 @@
+//go:build software
+// +build software
+
+package egl
+
+import (
+	"errors"
+	"testing"
+
+	"../openvg"
+)
+
+func TestCreatePbufferFromClientBuffer(t *testing.T) {
+	d := initTestDisplay(t)
+	rgba8888 := Config{d.handle, 1}
+	rgb565 := Config{d.handle, 3}
+	for _, test := range []struct {
+		config Config
+		format openvg.ImageFormat
+		want   error
+	}{
+		{rgba8888, openvg.ImageFormatSrgba8888, nil},
+		{rgba8888, openvg.ImageFormatLabgr8888Pre, nil},
+		{rgb565, openvg.ImageFormatSrgb565, nil},
+		{rgb565, openvg.ImageFormatSrgba8888, ErrBadMatch},
+		{rgba8888, openvg.ImageFormatSrgb565, ErrBadMatch},
+		{rgba8888, openvg.ImageFormatSrgbx8888, ErrBadMatch},
+		{rgba8888, openvg.ImageFormatA8, ErrBadMatch},
+	} {
+		img, err := openvg.CreateImage(test.format, 4, 4, nil)
+		if err != nil {
+			t.Fatalf("CreateImage(%v): %v", test.format, err)
+		}
+		s, err := d.CreatePbufferFromClientBuffer(test.config, img)
+		if test.want == nil && err != nil {
+			t.Errorf("CreatePbufferFromClientBuffer(config %d, %v): %v", test.config.id, test.format, err)
+		} else if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("CreatePbufferFromClientBuffer(config %d, %v) returned %v, want %v", test.config.id, test.format, err, test.want)
+		}
+		if err == nil {
+			d.DestroySurface(s)
+		}
+		img.Destroy()
+	}
+
+	img, err := openvg.CreateImage(openvg.ImageFormatSrgba8888, 4, 4, nil)
+	if err != nil {
+		t.Fatalf("CreateImage: %v", err)
+	}
+	img.Destroy()
+	if _, err := d.CreatePbufferFromClientBuffer(rgba8888, img); !errors.Is(err, ErrBadParameter) {
+		t.Errorf("CreatePbufferFromClientBuffer of a destroyed image returned %v, want %v", err, ErrBadParameter)
+	}
+}
@@ -1,116 +1,123 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
-	"os"
+	"runtime"
+	"time"
 
 	"../bcmhost"
 	"../egl"
 	"../ffmpeg"
 	"../openvg"
+	"../screen"
 )
 
+var (
+	videoSize   = flag.String("video_size", "", "requested frame size, e.g. 1280x720")
+	framerate   = flag.String("framerate", "", "requested frame rate, e.g. 30")
+	inputFormat = flag.String("input_format", "", "requested v4l2 format, e.g. mjpeg")
+	mirror      = flag.Bool("mirror", false, "flip the video horizontally")
+	fontFile    = flag.String("font", "", "TrueType font for a timestamp and FPS overlay")
+	screenshot  = flag.String("screenshot", "", "save the last displayed frame to this PNG file")
+	filters     = flag.String("filter", "", "comma-separated effects to apply, e.g. grayscale,blur")
+	cornerRad   = flag.Float64("corner_radius", 0, "round the corners of the video by this many pixels")
+	msaa        = flag.Int("msaa", 0, "prefer EGL configs with this many samples per pixel, e.g. 4 to antialias the overlay")
+	vsync       = flag.Bool("vsync", true, "wait for vertical sync when presenting frames")
+	queueSize   = flag.Int("queue", 2, "decoded frames to hold for display before dropping the oldest")
+	debugVG     = flag.Bool("debug_openvg", false, "check for OpenVG errors after every call and log their call sites")
+)
+
+func init() {
+	// EGL binds the context to the calling thread, so keep main, which creates
+	// the context and runs the render loop, on one OS thread.
+	runtime.LockOSThread()
+}
+
 func main() {
-	bcmhost.Init()
-	defer bcmhost.Deinit()
-	w, h, err := bcmhost.GraphicsGetDisplaySize(bcmhost.DispmanxIDMainLcd)
-	if err != nil {
-		log.Printf("bcmhost: %v", err)
-		return
+	flag.Parse()
+	if *debugVG {
+		openvg.SetDebug(openvg.LogErrors)
 	}
-	fmt.Printf("Display size: %d %d\n", w, h)
 
-	display, err := bcmhost.DispmanxDisplayOpen(bcmhost.DispmanxIDMainLcd)
-	if err != nil {
-		log.Printf("bcmhost: %v", err)
-		return
+	opts := screen.Options{
+		Display: bcmhost.DispmanxIDMainLcd,
+		Layer:   1,
+		Samples: *msaa,
 	}
-	defer display.Close()
-
-	update, err := display.UpdateStart(0 /*priority*/)
-	if err != nil {
-		log.Printf("bcmhost: %v", err)
-		return
+	if *cornerRad > 0 {
+		// The rounded corners are drawn through the alpha mask, which needs a
+		// config with mask bits.
+		opts.Config = egl.DefaultConfigAttribs().AlphaMaskSize(8)
 	}
-	dest := bcmhost.Rect{0, 0, w, h}
-	src := bcmhost.Rect{0, 0, w << 16, h << 16}
-	element, err := update.ElementAdd(
-		1, /*layer*/
-		dest,
-		bcmhost.DispmanxDefaultResource,
-		src,
-		bcmhost.DispmanxProtectionNone)
+	scr, err := screen.Open(opts)
 	if err != nil {
-		log.Printf("bcmhost: %v", err)
+		log.Print(err)
 		return
 	}
-
-	eglDisplay, err := egl.GetDisplay(egl.DefaultDisplay)
-	if err != nil {
-		log.Printf("egl: %v", err)
-		return
-	}
-	version, err := eglDisplay.Initialize()
-	if err != nil {
-		log.Printf("egl: %v", err)
-		return
+	defer scr.Close()
+	w, h := scr.Width(), scr.Height()
+	fmt.Printf("Display size: %d %d\n", w, h)
+	if version, err := scr.Display().QueryString(egl.Version); err == nil {
+		fmt.Printf("EGL version: %s\n", version)
 	}
-	fmt.Printf("EGL version: %s\n", version)
-	defer eglDisplay.Terminate()
-	egl.BindAPI(egl.APIOpenVG)
-	config, err := eglDisplay.ChooseConfig()
-	if err != nil {
-		log.Printf("egl: %v", err)
-		return
+	if vendor, err := scr.Display().QueryString(egl.Vendor); err == nil {
+		fmt.Printf("EGL vendor: %s\n", vendor)
 	}
-	window := bcmhost.NewDispmanxWindow(element, w, h)
-	surface, err := eglDisplay.CreateWindowSurface(config, window)
-	if err != nil {
-		log.Printf("egl: %v", err)
-		return
+	if samples, err := scr.Config().Attrib(egl.Samples); err == nil {
+		fmt.Printf("EGL samples: %d\n", samples)
 	}
-	ctx, err := eglDisplay.CreateContext(config)
-	if err != nil {
-		log.Printf("egl: %v", err)
-		return
+	swapInterval := 0
+	if *vsync {
+		swapInterval = 1
 	}
-	err = eglDisplay.MakeCurrent(surface, ctx)
-	if err != nil {
+	if err := scr.SetSwapInterval(swapInterval); err != nil {
 		log.Printf("egl: %v", err)
-		return
-	}
-	// TODO(robert): Defer release the context.
-	err = update.UpdateSubmit()
-	if err != nil {
-		log.Printf("bcmhost: %v", err)
-		return
 	}
 
-	inputFormat, err := ffmpeg.NewInputFormat("v4l2")
+	v4l2, err := ffmpeg.NewInputFormat("v4l2")
 	if err != nil {
 		log.Printf("Failed to find input format")
 		return
 	}
 
-	formatCtx, err := ffmpeg.NewFormatContext(os.Args[1], inputFormat)
+	var options ffmpeg.Dictionary
+	defer options.Free()
+	for key, value := range map[string]string{
+		"video_size":   *videoSize,
+		"framerate":    *framerate,
+		"input_format": *inputFormat,
+	} {
+		if value == "" {
+			continue
+		}
+		if err := options.Set(key, value); err != nil {
+			log.Printf("Failed to set option: %v", err)
+			return
+		}
+	}
+
+	formatCtx, err := ffmpeg.NewFormatContext(flag.Arg(0), v4l2, &options)
 	if err != nil {
 		fmt.Printf("Failed creating format context: %v\n", err)
 		return
 	}
 	defer formatCtx.Close()
+	if options.Len() > 0 {
+		log.Printf("Unused input options: %v", options.Keys())
+	}
 
-	formatCtx.Dump()
-
-	packet, err := formatCtx.ReadFrame()
-	if err != nil {
-		fmt.Printf("Failed to read frame: %v\n", err)
+	if err := formatCtx.FindStreamInfo(); err != nil {
+		log.Printf("Failed to find stream info: %v", err)
 		return
 	}
+	formatCtx.Dump()
 
-	stream, err := formatCtx.GetStream(0)
+	stream, err := formatCtx.FindBestStream(ffmpeg.MediaTypeVideo)
 	if err != nil {
-		log.Printf("Failed to get stream: %v", err)
+		log.Printf("Failed to find video stream: %v", err)
 		return
 	}
 
@@ -120,53 +127,286 @@ func main() {
 		return
 	}
 
-	codecCtx, err := codec.NewContext(stream.Codecpar())
+	codecCtx, err := codec.NewContext(stream.Codecpar(), nil /*options*/)
 	if err != nil {
 		log.Printf("Failed to create new codec context: %v", err)
 		return
 	}
 	defer codecCtx.Free()
 
-	err = codecCtx.SendPacket(packet)
-	if err != nil {
-		log.Printf("Failed to send packet: %v", err)
-		return
+	// Upload decoded frames as they are when VG has a format with the same
+	// layout, and convert them to VG_sRGBX_8888 otherwise.
+	vgFormat, direct := openvg.ImageFormatForPixFmt(codecCtx.PixelFormat().String())
+	if !direct {
+		vgFormat = openvg.ImageFormatSrgbx8888
+	}
+	if !vgFormat.Accelerated() {
+		log.Printf("%v images are not hardware accelerated", vgFormat)
+	}
+	var scaler ffmpeg.Scaler
+	if !direct {
+		scaler, err = ffmpeg.NewScaler(
+			codecCtx.Width(), codecCtx.Height(), codecCtx.PixelFormat(),
+			codecCtx.Width(), codecCtx.Height(), ffmpeg.PixelFormatByName(vgFormat.PixFmtName()),
+			ffmpeg.ScaleBilinear)
+		if err != nil {
+			log.Printf("Failed to create scaler: %v", err)
+			return
+		}
+		defer scaler.Free()
 	}
 
-	frame, err := ffmpeg.NewFrame()
-	if err != nil {
-		log.Printf("Failed to create new frame: %v", err)
-		return
+	var chain *openvg.FilterChain
+	if *filters != "" {
+		list, err := openvg.ParseFilters(*filters)
+		if err != nil {
+			log.Printf("Failed to parse filters: %v", err)
+			return
+		}
+		chain = openvg.NewFilterChain(list...)
+		defer chain.Destroy()
 	}
-	defer frame.Free()
 
+	// Frames are uploaded to the two buffers in turn, so writing a new frame
+	// never waits for the GPU to finish drawing the previous one.
+	var buffers [2]frameBuffer
+	for i := range buffers {
+		if buffers[i], err = newFrameBuffer(vgFormat, codecCtx.Width(), codecCtx.Height(), chain != nil); err != nil {
+			log.Printf("Failed to create image: %v", err)
+			return
+		}
+		defer buffers[i].destroy()
+	}
+
+	var overlay *openvg.Font
+	var overlayPaint openvg.Paint
+	if *fontFile != "" {
+		if overlay, err = openvg.LoadFontFile(*fontFile); err != nil {
+			log.Printf("Failed to load font: %v", err)
+			return
+		}
+		defer overlay.Destroy()
+		if overlayPaint, err = openvg.NewColorPaint(1, 1, 1, 1); err != nil {
+			log.Printf("Failed to create paint: %v", err)
+			return
+		}
+		defer overlayPaint.Destroy()
+		openvg.SetMatrixMode(openvg.MatrixPathUserToSurface)
+		openvg.LoadIdentity()
+	}
+	const overlaySize = 32
+	var fps float64
+	frames, fpsStart := 0, time.Now()
+
+	// Stream until the user presses Enter.
+	cctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		fmt.Scanln()
+		cancel()
+	}()
+
+	openvg.SetClearColor(0, 0, 0, 1)
+	screenRect := openvg.Rect{Width: float32(w), Height: float32(h)}
+	var videoMask openvg.Path
+	rounded := *cornerRad > 0
+	if rounded {
+		if videoMask, err = roundedVideoPath(codecCtx.Width(), codecCtx.Height(), screenRect, float32(*cornerRad)); err != nil {
+			log.Printf("Failed to mask video: %v", err)
+			return
+		}
+		defer videoMask.Destroy()
+	}
+	// Keep the overlay to a band along the top of the screen.
+	openvg.SetScissorRects([]openvg.PixelRect{{X: 0, Y: h - 2*overlaySize, Width: w, Height: 2 * overlaySize}})
+	draw := func(shown openvg.Image) error {
+		if err := openvg.Clear(0, 0, w, h); err != nil {
+			return err
+		}
+		if rounded {
+			// EGL leaves the mask undefined after a swap, so render it again
+			// for every frame.
+			if err := renderMask(videoMask); err != nil {
+				return err
+			}
+			openvg.SetMasking(true)
+		}
+		if err := shown.DrawIn(screenRect, openvg.ScaleLetterbox, *mirror); err != nil {
+			return err
+		}
+		openvg.SetMasking(false)
+		if overlay != nil {
+			text := fmt.Sprintf("%s  %.1f fps", time.Now().Format("15:04:05"), fps)
+			openvg.SetScissoring(true)
+			if err := overlay.DrawText(overlaySize/2, float32(h)-overlaySize*3/2, overlaySize, text, overlayPaint); err != nil {
+				log.Printf("Failed to draw overlay: %v", err)
+			}
+			openvg.SetScissoring(false)
+		}
+		return nil
+	}
+
+	// Convert frames on the decoder's side of the queue, which keeps only the
+	// most recent ones so a slow display never backs up the camera.
+	queue := ffmpeg.NewFrameQueue(*queueSize)
+	decoder := ffmpeg.NewDecoder(formatCtx, codecCtx, stream.Index())
+	go func() {
+		defer queue.Close()
+		for frame := range decoder.Frames(cctx) {
+			if direct {
+				queue.Push(frame)
+				continue
+			}
+			rgb, err := scaler.Scale(frame)
+			frame.Free()
+			if err != nil {
+				log.Printf("Failed to convert frame: %v", err)
+				cancel()
+				continue
+			}
+			queue.Push(rgb)
+		}
+	}()
+
+	// The render loop presents the newest frame, and redraws the last one when
+	// none arrives between two refresh ticks so a slow decoder never stalls
+	// the overlay. Ticks that follow a new frame are skipped, since with vsync
+	// every swap waits for a vertical blank that the next frame could use.
+	refresh := time.NewTicker(time.Second / 10)
+	defer refresh.Stop()
+	front := -1
+	arrived := false
+render:
 	for {
-		err = codecCtx.ReceiveFrame(&frame)
-		if err == nil {
-			break
+		select {
+		case frame, ok := <-queue.Frames():
+			if !ok {
+				break render
+			}
+			back := (front + 1) % len(buffers)
+			err := buffers[back].upload(frame, vgFormat, chain)
+			frame.Free()
+			if err != nil {
+				log.Printf("Failed to upload frame: %v", err)
+				cancel()
+				break render
+			}
+			front = back
+			arrived = true
+			frames++
+			if elapsed := time.Since(fpsStart); elapsed >= time.Second {
+				fps = float64(frames) / elapsed.Seconds()
+				frames, fpsStart = 0, time.Now()
+			}
+		case <-refresh.C:
+			if front < 0 || arrived {
+				arrived = false
+				continue
+			}
+		}
+		if err := draw(buffers[front].shown); err != nil {
+			log.Printf("Failed to draw frame: %v", err)
+			cancel()
+			break render
+		}
+		if err := scr.Swap(); err != nil {
+			log.Printf("Failed to swap buffers: %v", err)
+			cancel()
+			break render
 		}
-		fmt.Println("Couldn't get frame. Trying again...", err)
+	}
+	// Wait for the decoder to stop, freeing the frames still queued.
+	for frame := range queue.Frames() {
+		frame.Free()
+	}
+	if n := queue.Dropped(); n > 0 {
+		log.Printf("Dropped %d frames", n)
+	}
+	if err := decoder.Err(); err != nil && err != context.Canceled {
+		log.Printf("Failed decoding: %v", err)
 	}
 
-	img, err := openvg.CreateImage(
-		openvg.ImageFormatSrgbx8888,
-		codecCtx.Width(),
-		codecCtx.Height(),
-		[]openvg.ImageQuality{openvg.ImageQualityNonantialiased})
+	// The back buffer is undefined after a swap, so redraw the last frame to
+	// capture it.
+	if *screenshot != "" && front >= 0 {
+		if err := draw(buffers[front].shown); err != nil {
+			log.Printf("Failed to draw frame: %v", err)
+		} else if err := openvg.SavePNG(*screenshot, 0, 0, w, h); err != nil {
+			log.Printf("Failed to save screenshot: %v", err)
+		}
+	}
+}
+
+// frameBuffer holds an uploaded frame and the image drawn for it: the frame
+// itself, or the output of the filter chain.
+type frameBuffer struct {
+	frame, shown openvg.Image
+}
+
+func newFrameBuffer(format openvg.ImageFormat, width, height int, filtered bool) (frameBuffer, error) {
+	quality := []openvg.ImageQuality{openvg.ImageQualityNonantialiased}
+	frame, err := openvg.CreateImage(format, width, height, quality)
 	if err != nil {
-		log.Printf("Failed to create image: %v", err)
-		return
+		return frameBuffer{}, err
+	}
+	if !filtered {
+		return frameBuffer{frame, frame}, nil
+	}
+	shown, err := openvg.CreateImage(format, width, height, quality)
+	if err != nil {
+		frame.Destroy()
+		return frameBuffer{}, err
+	}
+	return frameBuffer{frame, shown}, nil
+}
+
+func (b frameBuffer) destroy() {
+	if b.shown != b.frame {
+		b.shown.Destroy()
 	}
+	b.frame.Destroy()
+}
 
-	img.Write(
-		frame.Data(),
-		frame.Linesize(),
-		openvg.ImageFormatSrgbx8888,
-		0 /*x*/, 0, /*y*/
-		codecCtx.Width(), codecCtx.Height())
+// upload writes frame, which must match the size and format of the buffer,
+// and applies chain if it is not nil.
+func (b frameBuffer) upload(frame ffmpeg.Frame, format openvg.ImageFormat, chain *openvg.FilterChain) error {
+	if err := b.frame.Write(frame.Data(), frame.Linesize(), format, 0 /*x*/, 0 /*y*/, b.frame.Width(), b.frame.Height()); err != nil {
+		return err
+	}
+	if chain != nil {
+		return chain.Apply(b.shown, b.frame)
+	}
+	return nil
+}
 
-	img.Draw()
+// roundedVideoPath returns a rounded rectangle over the area a width x height
+// video is letterboxed into, in surface coordinates.
+func roundedVideoPath(width, height int, screen openvg.Rect, radius float32) (openvg.Path, error) {
+	m := openvg.PlacementMatrix(width, height, screen, openvg.ScaleLetterbox, false)
+	x0, y0 := m.Apply(0, 0)
+	x1, y1 := m.Apply(float32(width), float32(height))
+	if x1 < x0 {
+		x0, x1 = x1, x0
+	}
+	if y1 < y0 {
+		y0, y1 = y1, y0
+	}
+	path, err := openvg.CreatePath()
+	if err != nil {
+		return openvg.Path{}, err
+	}
+	if err := path.RoundRect(x0, y0, x1-x0, y1-y0, 2*radius, 2*radius); err != nil {
+		path.Destroy()
+		return openvg.Path{}, err
+	}
+	return path, nil
+}
 
-	eglDisplay.SwapBuffers(surface)
-	fmt.Scanln()
+// renderMask sets the drawing surface mask to the fill of path, which is in
+// surface coordinates.
+func renderMask(path openvg.Path) error {
+	if err := openvg.SetMatrixMode(openvg.MatrixPathUserToSurface); err != nil {
+		return err
+	}
+	openvg.LoadIdentity()
+	return path.RenderToMask(openvg.FillPath, openvg.MaskSet)
 }
@@ -21,7 +21,7 @@ func main() {
 		fmt.Println(info.Name)
 	}
 
-	ctx, err := ffmpeg.NewFormatContext("/dev/video0", inputFormat)
+	ctx, err := ffmpeg.NewFormatContext("/dev/video0", inputFormat, nil /*options*/)
 	if err != nil {
 		log.Fatalf("Failed creating format context: %v", err)
 	}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"../ffmpeg"
+)
+
+var (
+	input   = flag.String("input", "/dev/video0", "device or file to record")
+	format  = flag.String("format", "v4l2", "input format, empty to probe")
+	output  = flag.String("output", "out.mkv", "file to record to; the extension selects the container")
+	encoder = flag.String("encoder", "mpeg4", "name of the video encoder, e.g. mpeg4, ffv1 or rawvideo")
+)
+
+func main() {
+	flag.Parse()
+
+	var inputFormat ffmpeg.InputFormat
+	if *format != "" {
+		var err error
+		inputFormat, err = ffmpeg.NewInputFormat(*format)
+		if err != nil {
+			log.Fatalf("Failed to find input format: %v", err)
+		}
+	}
+	in, err := ffmpeg.NewFormatContext(*input, inputFormat, nil /*options*/)
+	if err != nil {
+		log.Fatalf("Failed creating format context: %v", err)
+	}
+	defer in.Close()
+	if err := in.FindStreamInfo(); err != nil {
+		log.Fatalf("Failed to find stream info: %v", err)
+	}
+	stream, err := in.FindBestStream(ffmpeg.MediaTypeVideo)
+	if err != nil {
+		log.Fatalf("Failed to find video stream: %v", err)
+	}
+	decoder, err := ffmpeg.FindDecoder(stream.Codecpar().CodecID())
+	if err != nil {
+		log.Fatalf("Failed to find decoder: %v", err)
+	}
+	decoderCtx, err := decoder.NewContext(stream.Codecpar(), nil /*options*/)
+	if err != nil {
+		log.Fatalf("Failed to create decoder context: %v", err)
+	}
+	defer decoderCtx.Free()
+
+	out, err := ffmpeg.NewOutputFormatContext(*output, "" /*formatName*/)
+	if err != nil {
+		log.Fatalf("Failed creating output context: %v", err)
+	}
+	defer out.Close()
+	codec, err := ffmpeg.FindEncoderByName(*encoder)
+	if err != nil {
+		log.Fatalf("Failed to find encoder: %v", err)
+	}
+	frameRate := stream.FrameRate()
+	if frameRate.Num == 0 || frameRate.Den == 0 {
+		frameRate = ffmpeg.Rational{Num: 30, Den: 1}
+	}
+	// The encoder counts time in intervals of the nominal frame rate. Input
+	// timestamps are rescaled into it below, which keeps the recording in step
+	// with a source whose frame rate varies.
+	encoderCtx, err := codec.NewEncoderContext(ffmpeg.EncoderParams{
+		Width:        decoderCtx.Width(),
+		Height:       decoderCtx.Height(),
+		PixelFormat:  ffmpeg.PixelFormatYUV420P,
+		TimeBase:     ffmpeg.Rational{Num: frameRate.Den, Den: frameRate.Num},
+		FrameRate:    frameRate,
+		GlobalHeader: out.NeedsGlobalHeader(),
+	}, nil /*options*/)
+	if err != nil {
+		log.Fatalf("Failed to create encoder context: %v", err)
+	}
+	defer encoderCtx.Free()
+	outStream, err := out.NewStream(encoderCtx)
+	if err != nil {
+		log.Fatalf("Failed to create output stream: %v", err)
+	}
+	if err := out.WriteHeader(nil /*options*/); err != nil {
+		log.Fatalf("Failed to write header: %v", err)
+	}
+
+	scaler, err := ffmpeg.NewScaler(
+		decoderCtx.Width(), decoderCtx.Height(), decoderCtx.PixelFormat(),
+		encoderCtx.Width(), encoderCtx.Height(), ffmpeg.PixelFormatYUV420P,
+		ffmpeg.ScaleBilinear)
+	if err != nil {
+		log.Fatalf("Failed to create scaler: %v", err)
+	}
+	defer scaler.Free()
+
+	// Record until interrupted or the input ends.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	enc := ffmpeg.NewEncoder(out, encoderCtx, outStream)
+	dec := ffmpeg.NewDecoder(in, decoderCtx, stream.Index())
+	var (
+		start    = ffmpeg.NoPTS
+		lastPTS  = int64(-1)
+		recorded int
+	)
+	for frame := range dec.Frames(ctx) {
+		// Timestamps count from the first frame. A frame without one follows
+		// the previous frame by one interval, and a frame that does not
+		// advance past the previous one in the encoder's time base is dropped.
+		pts := lastPTS + 1
+		if inPTS := frame.PTS(); inPTS != ffmpeg.NoPTS {
+			if start == ffmpeg.NoPTS {
+				start = inPTS
+			}
+			pts = ffmpeg.RescaleQ(inPTS-start, stream.TimeBase(), encoderCtx.TimeBase())
+		}
+		if pts <= lastPTS {
+			frame.Free()
+			continue
+		}
+		yuv, err := scaler.Scale(frame)
+		frame.Free()
+		if err != nil {
+			log.Printf("Failed to convert frame: %v", err)
+			cancel()
+			continue
+		}
+		yuv.SetPTS(pts)
+		err = enc.Encode(yuv)
+		yuv.Free()
+		if err != nil {
+			log.Printf("Failed to encode frame: %v", err)
+			cancel()
+			continue
+		}
+		lastPTS = pts
+		recorded++
+	}
+	if err := dec.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		log.Printf("Failed decoding: %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		log.Printf("Failed to flush encoder: %v", err)
+	}
+	if err := out.WriteTrailer(); err != nil {
+		log.Printf("Failed to write trailer: %v", err)
+	}
+	log.Printf("Recorded %d frames to %s", recorded, *output)
+}
@@ -2,11 +2,12 @@ package ffmpeg
 
 /*
   #cgo pkg-config: libavcodec
+  #include <stdlib.h>
   #include <libavcodec/avcodec.h>
+  #include <libavutil/pixdesc.h>
 */
 import "C"
 import (
-	"errors"
 	"fmt"
 	"unsafe"
 )
@@ -21,6 +22,10 @@ type CodecID C.enum_AVCodecID
 // Supported codec IDs.
 const (
 	CodecIDRawVideo = CodecID(C.AV_CODEC_ID_RAWVIDEO)
+	CodecIDMJPEG    = CodecID(C.AV_CODEC_ID_MJPEG)
+	CodecIDMPEG4    = CodecID(C.AV_CODEC_ID_MPEG4)
+	CodecIDH264     = CodecID(C.AV_CODEC_ID_H264)
+	CodecIDFFV1     = CodecID(C.AV_CODEC_ID_FFV1)
 )
 
 // CodecParameters wraps a AVCodecParameters.
@@ -33,6 +38,26 @@ func (p CodecParameters) CodecID() CodecID {
 	return CodecID(C.enum_AVCodecID(p.cptr.codec_id))
 }
 
+// MediaType wraps AVCodecParameters.codec_type.
+func (p CodecParameters) MediaType() MediaType {
+	return MediaType(p.cptr.codec_type)
+}
+
+// Width wraps AVCodecParameters.width.
+func (p CodecParameters) Width() int {
+	return int(p.cptr.width)
+}
+
+// Height wraps AVCodecParameters.height.
+func (p CodecParameters) Height() int {
+	return int(p.cptr.height)
+}
+
+// PixelFormat wraps AVCodecParameters.format for video streams.
+func (p CodecParameters) PixelFormat() PixelFormat {
+	return PixelFormat(p.cptr.format)
+}
+
 // Codec wraps an AVCodec.
 type Codec struct {
 	cptr *C.AVCodec
@@ -42,7 +67,27 @@ type Codec struct {
 func FindDecoder(id CodecID) (Codec, error) {
 	cptr := C.avcodec_find_decoder(C.enum_AVCodecID(id))
 	if cptr == nil {
-		return Codec{}, fmt.Errorf("ffmpeg: could not find decoder for %v", id)
+		return Codec{}, newError(fmt.Sprintf("could not find decoder for %v", id), ErrDecoderNotFound.Code)
+	}
+	return Codec{cptr}, nil
+}
+
+// FindEncoder wraps avcodec_find_encoder.
+func FindEncoder(id CodecID) (Codec, error) {
+	cptr := C.avcodec_find_encoder(C.enum_AVCodecID(id))
+	if cptr == nil {
+		return Codec{}, newError(fmt.Sprintf("could not find encoder for %v", id), ErrEncoderNotFound.Code)
+	}
+	return Codec{cptr}, nil
+}
+
+// FindEncoderByName wraps avcodec_find_encoder_by_name.
+func FindEncoderByName(name string) (Codec, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	cptr := C.avcodec_find_encoder_by_name(cname)
+	if cptr == nil {
+		return Codec{}, newError(fmt.Sprintf("could not find encoder %q", name), ErrEncoderNotFound.Code)
 	}
 	return Codec{cptr}, nil
 }
@@ -57,17 +102,68 @@ type CodecContext struct {
 	cptr *C.AVCodecContext
 }
 
-// NewContext wraps avcodec_alloc_context3.
-func (c Codec) NewContext(params CodecParameters) (CodecContext, error) {
+// NewContext wraps avcodec_alloc_context3 and avcodec_open2. options may be
+// nil; otherwise, on return it holds only the options the codec did not
+// recognize.
+func (c Codec) NewContext(params CodecParameters, options *Dictionary) (CodecContext, error) {
 	cptr := C.avcodec_alloc_context3(c.cptr)
 	if cptr == nil {
-		return CodecContext{}, fmt.Errorf("ffmpeg: failed creating context for codec ID %v", c.CodecID())
+		return CodecContext{}, newError(fmt.Sprintf("failed creating context for codec ID %v", c.CodecID()), ErrNoMemory.Code)
 	}
 	if result := C.avcodec_parameters_to_context(cptr, params.cptr); result < 0 {
-		return CodecContext{}, fmt.Errorf("ffmpeg: failed to copy parameters to new context: %v", getErrStr(result))
+		C.avcodec_free_context(&cptr)
+		return CodecContext{}, newError("failed to copy parameters to new context", int(result))
+	}
+	if result := C.avcodec_open2(cptr, c.cptr, dictPtr(options)); result < 0 {
+		C.avcodec_free_context(&cptr)
+		return CodecContext{}, newError("failed to open codec context", int(result))
+	}
+	return CodecContext{cptr}, nil
+}
+
+// EncoderParams configures a video encoder created by NewEncoderContext.
+type EncoderParams struct {
+	Width, Height int
+	PixelFormat   PixelFormat
+	// TimeBase is the unit of the PTS of frames sent to the encoder.
+	TimeBase  Rational
+	FrameRate Rational
+	// BitRate is the target bit rate in bits per second; 0 leaves the codec
+	// default.
+	BitRate int64
+	// GOPSize is the maximum distance between key frames; 0 leaves the codec
+	// default.
+	GOPSize int
+	// GlobalHeader must be set when the output format requires it, see
+	// FormatContext.NeedsGlobalHeader.
+	GlobalHeader bool
+}
+
+// NewEncoderContext wraps avcodec_alloc_context3 and avcodec_open2 for an
+// encoder. options may be nil; otherwise, on return it holds only the options
+// the codec did not recognize.
+func (c Codec) NewEncoderContext(params EncoderParams, options *Dictionary) (CodecContext, error) {
+	cptr := C.avcodec_alloc_context3(c.cptr)
+	if cptr == nil {
+		return CodecContext{}, newError(fmt.Sprintf("failed creating context for codec ID %v", c.CodecID()), ErrNoMemory.Code)
+	}
+	cptr.width = C.int(params.Width)
+	cptr.height = C.int(params.Height)
+	cptr.pix_fmt = C.enum_AVPixelFormat(params.PixelFormat)
+	cptr.time_base = params.TimeBase.c()
+	cptr.framerate = params.FrameRate.c()
+	if params.BitRate > 0 {
+		cptr.bit_rate = C.int64_t(params.BitRate)
+	}
+	if params.GOPSize > 0 {
+		cptr.gop_size = C.int(params.GOPSize)
 	}
-	if result := C.avcodec_open2(cptr, c.cptr, nil /*options*/); result < 0 {
-		return CodecContext{}, fmt.Errorf("ffmpeg: failed to open codec context: %v", getErrStr(result))
+	if params.GlobalHeader {
+		cptr.flags |= C.AV_CODEC_FLAG_GLOBAL_HEADER
+	}
+	if result := C.avcodec_open2(cptr, c.cptr, dictPtr(options)); result < 0 {
+		C.avcodec_free_context(&cptr)
+		return CodecContext{}, newError("failed to open codec context", int(result))
 	}
 	return CodecContext{cptr}, nil
 }
@@ -87,10 +183,20 @@ func (ctx CodecContext) Height() int {
 	return int(ctx.cptr.height)
 }
 
+// PixelFormat wraps AVCodecContext.pix_fmt.
+func (ctx CodecContext) PixelFormat() PixelFormat {
+	return PixelFormat(ctx.cptr.pix_fmt)
+}
+
+// TimeBase wraps AVCodecContext.time_base.
+func (ctx CodecContext) TimeBase() Rational {
+	return newRational(ctx.cptr.time_base)
+}
+
 // SendPacket wraps avcodec_send_packet.
 func (ctx CodecContext) SendPacket(p Packet) error {
 	if result := C.avcodec_send_packet(ctx.cptr, p.cptr); result < 0 {
-		return fmt.Errorf("ffmpeg: failed to send package: [%d] %s", result, getErrStr(result))
+		return newError("failed to send packet", int(result))
 	}
 	return nil
 }
@@ -98,7 +204,24 @@ func (ctx CodecContext) SendPacket(p Packet) error {
 // ReceiveFrame wraps avcodec_receive_frame.
 func (ctx CodecContext) ReceiveFrame(frame *Frame) error {
 	if result := C.avcodec_receive_frame(ctx.cptr, frame.cptr); result < 0 {
-		return fmt.Errorf("ffmpeg: failed receiving frame: [%d] %s", result, getErrStr(result))
+		return newError("failed receiving frame", int(result))
+	}
+	return nil
+}
+
+// SendFrame wraps avcodec_send_frame. Sending the zero Frame flushes the
+// encoder.
+func (ctx CodecContext) SendFrame(f Frame) error {
+	if result := C.avcodec_send_frame(ctx.cptr, f.cptr); result < 0 {
+		return newError("failed to send frame", int(result))
+	}
+	return nil
+}
+
+// ReceivePacket wraps avcodec_receive_packet.
+func (ctx CodecContext) ReceivePacket(p *Packet) error {
+	if result := C.avcodec_receive_packet(ctx.cptr, p.cptr); result < 0 {
+		return newError("failed receiving packet", int(result))
 	}
 	return nil
 }
@@ -112,7 +235,24 @@ type Frame struct {
 func NewFrame() (Frame, error) {
 	f := Frame{C.av_frame_alloc()}
 	if f.cptr == nil {
-		return f, errors.New("ffmpeg: failed to allocate a new frame")
+		return f, newError("failed to allocate a new frame", ErrNoMemory.Code)
+	}
+	return f, nil
+}
+
+// NewVideoFrame wraps av_frame_alloc and av_frame_get_buffer, returning a
+// frame with buffers for every plane of the given size and pixel format.
+func NewVideoFrame(width, height int, format PixelFormat) (Frame, error) {
+	f, err := NewFrame()
+	if err != nil {
+		return f, err
+	}
+	f.cptr.width = C.int(width)
+	f.cptr.height = C.int(height)
+	f.cptr.format = C.int(format)
+	if result := C.av_frame_get_buffer(f.cptr, frameAlign); result < 0 {
+		f.Free()
+		return Frame{}, newError("failed to allocate frame buffer", int(result))
 	}
 	return f, nil
 }
@@ -122,12 +262,64 @@ func (f Frame) Free() {
 	C.av_frame_free(&f.cptr)
 }
 
+// Width wraps AVFrame.width.
+func (f Frame) Width() int {
+	return int(f.cptr.width)
+}
+
+// Height wraps AVFrame.height.
+func (f Frame) Height() int {
+	return int(f.cptr.height)
+}
+
+// Format wraps AVFrame.format.
+func (f Frame) Format() PixelFormat {
+	return PixelFormat(f.cptr.format)
+}
+
+// PTS wraps AVFrame.pts.
+func (f Frame) PTS() int64 {
+	return int64(f.cptr.pts)
+}
+
+// SetPTS sets AVFrame.pts.
+func (f Frame) SetPTS(pts int64) {
+	f.cptr.pts = C.int64_t(pts)
+}
+
+// NumPlanes wraps av_pix_fmt_count_planes for the frame's pixel format.
+func (f Frame) NumPlanes() int {
+	n := int(C.av_pix_fmt_count_planes(C.enum_AVPixelFormat(f.cptr.format)))
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
 // Linesize wraps AVFrame.linesize[0].
 func (f Frame) Linesize() int {
-	return int(f.cptr.linesize[0])
+	return f.PlaneLinesize(0)
 }
 
 // Data wraps AVFrame.data[0].
 func (f Frame) Data() unsafe.Pointer {
-	return unsafe.Pointer(f.cptr.data[0])
+	return f.PlaneData(0)
+}
+
+// PlaneLinesize wraps AVFrame.linesize[i]. It returns 0 for planes the frame
+// does not have.
+func (f Frame) PlaneLinesize(i int) int {
+	if i < 0 || i >= C.AV_NUM_DATA_POINTERS {
+		return 0
+	}
+	return int(f.cptr.linesize[i])
+}
+
+// PlaneData wraps AVFrame.data[i]. It returns nil for planes the frame does
+// not have.
+func (f Frame) PlaneData(i int) unsafe.Pointer {
+	if i < 0 || i >= C.AV_NUM_DATA_POINTERS {
+		return nil
+	}
+	return unsafe.Pointer(f.cptr.data[i])
 }
@@ -1,13 +1,12 @@
 package ffmpeg
 
 /*
-	#cgo arm,linux pkg-config: libavdevice
+	#cgo linux pkg-config: libavdevice
 	#include <libavdevice/avdevice.h>
 */
 import "C"
 
 import (
-	"fmt"
 	"unsafe"
 )
 
@@ -26,7 +25,7 @@ func ListInputSources(inputFormat InputFormat) ([]DeviceInfo, error) {
 	var listPtr *C.AVDeviceInfoList
 	result := C.avdevice_list_input_sources(inputFormat.cptr, nil, (*C.AVDictionary)(nil), &listPtr)
 	if result < 0 {
-		return nil, fmt.Errorf("ffmpeg: failed to list input sources: [%d] %s", result, getErrStr(result))
+		return nil, newError("failed to list input sources", int(result))
 	}
 	defer C.avdevice_free_list_devices(&listPtr)
 	devices := make([]DeviceInfo, listPtr.nb_devices)
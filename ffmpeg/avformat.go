@@ -1,17 +1,15 @@
 package ffmpeg
 
 import (
-	"errors"
 	"fmt"
-	"strings"
+	"time"
 	"unsafe"
 )
 
 /*
   #cgo amd64,windows LDFLAGS: -lavformat
-  #cgo arm,linux pkg-config: libavformat libavcodec
+  #cgo linux pkg-config: libavformat libavcodec
   #include <libavformat/avformat.h>
-  #include <libavutil/error.h>
 */
 import "C"
 
@@ -24,6 +22,7 @@ type InputFormat struct {
 type FormatContext struct {
 	cptr           *C.AVFormatContext
 	SourceFilename string
+	output         bool
 }
 
 // Packet wraps a AVPacket.
@@ -35,34 +34,47 @@ type Packet struct {
 func NewInputFormat(shortName string) (InputFormat, error) {
 	cstr := C.CString(shortName)
 	defer C.free(unsafe.Pointer(cstr))
-	return InputFormat{C.av_find_input_format(cstr)}, nil
+	cptr := C.av_find_input_format(cstr)
+	if cptr == nil {
+		return InputFormat{}, newError(fmt.Sprintf("could not find input format %q", shortName), ErrDemuxerNotFound.Code)
+	}
+	return InputFormat{cptr}, nil
 }
 
-// NewFormatContext wraps avformat_open_input().
-func NewFormatContext(filename string, inputFormat InputFormat) (FormatContext, error) {
+// NewFormatContext wraps avformat_open_input(). options may be nil; otherwise,
+// on return it holds only the options the demuxer did not recognize.
+func NewFormatContext(filename string, inputFormat InputFormat, options *Dictionary) (FormatContext, error) {
 	var ctxp *C.AVFormatContext
 	filenamecs := C.CString(filename)
 	defer C.free(unsafe.Pointer(filenamecs))
-	if result := C.avformat_open_input(&ctxp, filenamecs, inputFormat.cptr, nil /*options*/); result < 0 {
-		return FormatContext{}, fmt.Errorf("ffmpeg: failed to create context: %s", getErrStr(result))
+	if result := C.avformat_open_input(&ctxp, filenamecs, inputFormat.cptr, dictPtr(options)); result < 0 {
+		return FormatContext{}, newError("failed to create context", int(result))
 	}
-	return FormatContext{ctxp, filename}, nil
+	return FormatContext{ctxp, filename, false}, nil
 }
 
-// Close wraps avformat_close_input.
+// Close wraps avformat_close_input, or avio_closep and avformat_free_context
+// for output contexts.
 func (ctx FormatContext) Close() {
-	C.avformat_close_input(&ctx.cptr)
+	if !ctx.output {
+		C.avformat_close_input(&ctx.cptr)
+		return
+	}
+	if ctx.cptr.oformat.flags&C.AVFMT_NOFILE == 0 {
+		C.avio_closep(&ctx.cptr.pb)
+	}
+	C.avformat_free_context(ctx.cptr)
 }
 
 // ReadFrame wraps av_read_frame.
 func (ctx FormatContext) ReadFrame() (Packet, error) {
 	var p Packet
 	if p.cptr = C.av_packet_alloc(); p.cptr == nil {
-		return p, errors.New("ffmpeg: failed to alloc packet")
+		return p, newError("failed to alloc packet", ErrNoMemory.Code)
 	}
 	if result := C.av_read_frame(ctx.cptr, p.cptr); result < 0 {
 		defer p.Free()
-		return p, fmt.Errorf("ffmpeg: failed to read frame: [%d] %v", result, getErrStr(result))
+		return p, newError("failed to read frame", int(result))
 	}
 	return p, nil
 }
@@ -71,7 +83,40 @@ func (ctx FormatContext) ReadFrame() (Packet, error) {
 func (ctx FormatContext) Dump() {
 	cstr := C.CString(ctx.SourceFilename)
 	defer C.free(unsafe.Pointer(cstr))
-	C.av_dump_format(ctx.cptr, 0, cstr, 0 /*is_output*/)
+	isOutput := C.int(0)
+	if ctx.output {
+		isOutput = 1
+	}
+	C.av_dump_format(ctx.cptr, 0, cstr, isOutput)
+}
+
+// NewPacket wraps av_packet_alloc.
+func NewPacket() (Packet, error) {
+	p := Packet{C.av_packet_alloc()}
+	if p.cptr == nil {
+		return p, newError("failed to alloc packet", ErrNoMemory.Code)
+	}
+	return p, nil
+}
+
+// Unref wraps av_packet_unref so that the packet can be reused.
+func (p Packet) Unref() {
+	C.av_packet_unref(p.cptr)
+}
+
+// StreamIndex wraps AVPacket.stream_index.
+func (p Packet) StreamIndex() int {
+	return int(p.cptr.stream_index)
+}
+
+// SetStreamIndex sets AVPacket.stream_index.
+func (p Packet) SetStreamIndex(i int) {
+	p.cptr.stream_index = C.int(i)
+}
+
+// RescaleTS wraps av_packet_rescale_ts.
+func (p Packet) RescaleTS(from, to Rational) {
+	C.av_packet_rescale_ts(p.cptr, from.c(), to.c())
 }
 
 // Free unref counts the packet and frees it.
@@ -85,19 +130,128 @@ type Stream struct {
 	cptr *C.AVStream
 }
 
+// MediaType represents an AVMediaType.
+type MediaType C.enum_AVMediaType
+
+// Supported media types.
+const (
+	MediaTypeUnknown  = MediaType(C.AVMEDIA_TYPE_UNKNOWN)
+	MediaTypeVideo    = MediaType(C.AVMEDIA_TYPE_VIDEO)
+	MediaTypeAudio    = MediaType(C.AVMEDIA_TYPE_AUDIO)
+	MediaTypeData     = MediaType(C.AVMEDIA_TYPE_DATA)
+	MediaTypeSubtitle = MediaType(C.AVMEDIA_TYPE_SUBTITLE)
+)
+
+// Rational wraps an AVRational.
+type Rational struct {
+	Num, Den int
+}
+
+func newRational(r C.AVRational) Rational {
+	return Rational{int(r.num), int(r.den)}
+}
+
+func (r Rational) c() C.AVRational {
+	return C.AVRational{C.int(r.Num), C.int(r.Den)}
+}
+
+// Float64 wraps av_q2d. It returns 0 for a zero denominator.
+func (r Rational) Float64() float64 {
+	if r.Den == 0 {
+		return 0
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+// NoPTS is AV_NOPTS_VALUE, the timestamp of frames and packets without one.
+const NoPTS = int64(C.AV_NOPTS_VALUE)
+
+// RescaleQ wraps av_rescale_q, converting the timestamp ts from time base from
+// to time base to, rounding to nearest.
+func RescaleQ(ts int64, from, to Rational) int64 {
+	return int64(C.av_rescale_q(C.int64_t(ts), from.c(), to.c()))
+}
+
+// FindStreamInfo wraps avformat_find_stream_info. It probes the input so that
+// stream parameters missing from the container header become available.
+func (ctx FormatContext) FindStreamInfo() error {
+	if result := C.avformat_find_stream_info(ctx.cptr, nil /*options*/); result < 0 {
+		return newError("failed to find stream info", int(result))
+	}
+	return nil
+}
+
+// Streams wraps AVFormatContext.streams.
+func (ctx FormatContext) Streams() []Stream {
+	n := int(ctx.cptr.nb_streams)
+	if n == 0 {
+		return nil
+	}
+	cstreams := (*[1 << 20]*C.AVStream)(unsafe.Pointer(ctx.cptr.streams))[:n:n]
+	streams := make([]Stream, n)
+	for i, cptr := range cstreams {
+		streams[i] = Stream{cptr}
+	}
+	return streams
+}
+
+// GetStream wraps AVFormatContext.streams[i].
 func (ctx FormatContext) GetStream(i int) (Stream, error) {
-	q := uintptr(unsafe.Pointer(*ctx.cptr.streams)) + (uintptr(i) * unsafe.Sizeof(*ctx.cptr.streams))
-	return Stream{(*C.AVStream)(unsafe.Pointer(q))}, nil
+	streams := ctx.Streams()
+	if i < 0 || i >= len(streams) {
+		return Stream{}, newError(fmt.Sprintf("no stream %d in %d streams", i, len(streams)), ErrStreamNotFound.Code)
+	}
+	return streams[i], nil
 }
 
-func (s Stream) Codecpar() CodecParameters {
-	return CodecParameters{s.cptr.codecpar}
+// FindBestStream wraps av_find_best_stream.
+func (ctx FormatContext) FindBestStream(mediaType MediaType) (Stream, error) {
+	result := C.av_find_best_stream(
+		ctx.cptr,
+		C.enum_AVMediaType(mediaType),
+		-1,  /*wanted_stream_nb*/
+		-1,  /*related_stream*/
+		nil, /*decoder_ret*/
+		0 /*flags*/)
+	if result < 0 {
+		return Stream{}, newError("failed to find best stream", int(result))
+	}
+	return ctx.GetStream(int(result))
+}
+
+// Index wraps AVStream.index.
+func (s Stream) Index() int {
+	return int(s.cptr.index)
+}
+
+// TimeBase wraps AVStream.time_base.
+func (s Stream) TimeBase() Rational {
+	return newRational(s.cptr.time_base)
+}
+
+// FrameRate wraps AVStream.avg_frame_rate, falling back to
+// AVStream.r_frame_rate when the average is unknown.
+func (s Stream) FrameRate() Rational {
+	if r := newRational(s.cptr.avg_frame_rate); r.Num != 0 && r.Den != 0 {
+		return r
+	}
+	return newRational(s.cptr.r_frame_rate)
 }
 
-// getErrStr gets the corresponding error message for the given result code.
-func getErrStr(result C.int) string {
-	errStr := C.CString(strings.Repeat(" ", C.AV_ERROR_MAX_STRING_SIZE))
-	defer C.free(unsafe.Pointer(errStr))
-	C.av_strerror(result, errStr, C.AV_ERROR_MAX_STRING_SIZE)
-	return C.GoString(errStr)
+// Duration wraps AVStream.duration. It returns 0 if the duration is unknown.
+func (s Stream) Duration() time.Duration {
+	if s.cptr.duration == C.AV_NOPTS_VALUE {
+		return 0
+	}
+	tb := s.TimeBase()
+	if tb.Den == 0 {
+		return 0
+	}
+	us := C.av_rescale(s.cptr.duration, C.int64_t(tb.Num)*1000000, C.int64_t(tb.Den))
+	return time.Duration(us) * time.Microsecond
+}
+
+// Codecpar wraps AVStream.codecpar.
+func (s Stream) Codecpar() CodecParameters {
+	return CodecParameters{s.cptr.codecpar}
 }
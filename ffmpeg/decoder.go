@@ -0,0 +1,132 @@
+package ffmpeg
+
+/*
+  #include <libavcodec/avcodec.h>
+  #include <libavformat/avformat.h>
+*/
+import "C"
+import (
+	"context"
+	"sync"
+)
+
+// Decoder continuously demuxes the packets of a single stream from a
+// FormatContext and decodes them with a CodecContext.
+type Decoder struct {
+	format FormatContext
+	codec  CodecContext
+	stream int
+	start  sync.Once
+	frames chan Frame
+	err    error
+}
+
+// NewDecoder returns a Decoder for the stream at the given index of format.
+// The Decoder does not take ownership of format or codec; the caller must
+// close and free them once the frame channel has been closed.
+func NewDecoder(format FormatContext, codec CodecContext, stream int) *Decoder {
+	return &Decoder{format: format, codec: codec, stream: stream}
+}
+
+// Frames starts decoding on a new goroutine and returns the channel on which
+// decoded frames are delivered. The channel is closed when the input reaches
+// EOF, when ctx is cancelled or when decoding fails; Err reports which. The
+// receiver owns every Frame it receives and must Free it. Only the first call
+// starts decoding; later calls return the same channel and ignore ctx.
+func (d *Decoder) Frames(ctx context.Context) <-chan Frame {
+	d.start.Do(func() {
+		d.frames = make(chan Frame)
+		go func() {
+			defer close(d.frames)
+			d.err = d.decode(ctx, d.frames)
+		}()
+	})
+	return d.frames
+}
+
+// Err returns the error that stopped the Decoder, or nil if it stopped at EOF.
+// It must only be called after the channel returned by Frames is closed.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+func (d *Decoder) decode(ctx context.Context, frames chan<- Frame) error {
+	pkt := C.av_packet_alloc()
+	if pkt == nil {
+		return newError("failed to alloc packet", ErrNoMemory.Code)
+	}
+	defer C.av_packet_free(&pkt)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result := C.av_read_frame(d.format.cptr, pkt)
+		if int(result) == ErrEOF.Code {
+			break
+		}
+		if result < 0 {
+			return newError("failed to read frame", int(result))
+		}
+		if int(pkt.stream_index) != d.stream {
+			C.av_packet_unref(pkt)
+			continue
+		}
+		err := d.send(ctx, pkt, frames)
+		C.av_packet_unref(pkt)
+		if err != nil {
+			return err
+		}
+	}
+
+	// A nil packet puts the codec into draining mode so that any buffered
+	// frames are returned before it reports EOF.
+	if err := d.send(ctx, nil, frames); err != nil {
+		return err
+	}
+	return nil
+}
+
+// send feeds pkt to the codec and delivers every frame it produces. If the
+// codec refuses the packet until output is consumed, send drains the codec and
+// retries.
+func (d *Decoder) send(ctx context.Context, pkt *C.AVPacket, frames chan<- Frame) error {
+	for {
+		result := C.avcodec_send_packet(d.codec.cptr, pkt)
+		if result < 0 && int(result) != ErrAgain.Code {
+			return newError("failed to send packet", int(result))
+		}
+		if err := d.receive(ctx, frames); err != nil {
+			return err
+		}
+		if result == 0 {
+			return nil
+		}
+	}
+}
+
+// receive delivers decoded frames until the codec needs more input or has
+// been fully drained.
+func (d *Decoder) receive(ctx context.Context, frames chan<- Frame) error {
+	for {
+		frame, err := NewFrame()
+		if err != nil {
+			return err
+		}
+		result := C.avcodec_receive_frame(d.codec.cptr, frame.cptr)
+		if int(result) == ErrAgain.Code || int(result) == ErrEOF.Code {
+			frame.Free()
+			return nil
+		}
+		if result < 0 {
+			frame.Free()
+			return newError("failed receiving frame", int(result))
+		}
+		select {
+		case frames <- frame:
+		case <-ctx.Done():
+			frame.Free()
+			return ctx.Err()
+		}
+	}
+}
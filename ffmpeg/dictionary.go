@@ -0,0 +1,98 @@
+package ffmpeg
+
+/*
+  #include <stdlib.h>
+  #include <libavutil/dict.h>
+*/
+import "C"
+import (
+	"fmt"
+	"sort"
+	"unsafe"
+)
+
+// Dictionary wraps an AVDictionary. It is used to pass options such as
+// "video_size", "framerate" or "input_format" to demuxers and codecs. The zero
+// value is an empty dictionary.
+type Dictionary struct {
+	cptr *C.AVDictionary
+}
+
+// NewDictionary returns a Dictionary holding the given entries.
+func NewDictionary(entries map[string]string) (Dictionary, error) {
+	var d Dictionary
+	for k, v := range entries {
+		if err := d.Set(k, v); err != nil {
+			d.Free()
+			return Dictionary{}, err
+		}
+	}
+	return d, nil
+}
+
+// Set wraps av_dict_set.
+func (d *Dictionary) Set(key, value string) error {
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	cvalue := C.CString(value)
+	defer C.free(unsafe.Pointer(cvalue))
+	if result := C.av_dict_set(&d.cptr, ckey, cvalue, 0 /*flags*/); result < 0 {
+		return newError(fmt.Sprintf("failed to set option %q", key), int(result))
+	}
+	return nil
+}
+
+// Get wraps av_dict_get.
+func (d Dictionary) Get(key string) (string, bool) {
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+	entry := C.av_dict_get(d.cptr, ckey, nil /*prev*/, 0 /*flags*/)
+	if entry == nil {
+		return "", false
+	}
+	return C.GoString(entry.value), true
+}
+
+// Len wraps av_dict_count.
+func (d Dictionary) Len() int {
+	return int(C.av_dict_count(d.cptr))
+}
+
+// Keys returns the sorted keys of all entries.
+func (d Dictionary) Keys() []string {
+	var keys []string
+	for k := range d.Map() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Map copies all entries into a Go map.
+func (d Dictionary) Map() map[string]string {
+	m := make(map[string]string, d.Len())
+	empty := C.CString("")
+	defer C.free(unsafe.Pointer(empty))
+	var entry *C.AVDictionaryEntry
+	for {
+		entry = C.av_dict_get(d.cptr, empty, entry, C.AV_DICT_IGNORE_SUFFIX)
+		if entry == nil {
+			return m
+		}
+		m[C.GoString(entry.key)] = C.GoString(entry.value)
+	}
+}
+
+// Free wraps av_dict_free.
+func (d *Dictionary) Free() {
+	C.av_dict_free(&d.cptr)
+}
+
+// dictPtr returns the AVDictionary** to pass to functions that consume
+// options, or nil when no options were given.
+func dictPtr(d *Dictionary) **C.AVDictionary {
+	if d == nil {
+		return nil
+	}
+	return &d.cptr
+}
@@ -0,0 +1,77 @@
+package ffmpeg
+
+/*
+  #include <libavcodec/avcodec.h>
+  #include <libavformat/avformat.h>
+*/
+import "C"
+
+// Encoder encodes frames with a CodecContext and muxes the resulting packets
+// into a stream of an output FormatContext.
+type Encoder struct {
+	format FormatContext
+	codec  CodecContext
+	stream Stream
+}
+
+// NewEncoder returns an Encoder that writes to stream of format. The Encoder
+// does not take ownership of format or codec. The header must be written
+// before the first call to Encode.
+func NewEncoder(format FormatContext, codec CodecContext, stream Stream) *Encoder {
+	return &Encoder{format: format, codec: codec, stream: stream}
+}
+
+// Encode sends frame to the encoder and writes every packet it produces. The
+// frame's PTS must be in the time base of the codec. The caller keeps
+// ownership of frame.
+func (e *Encoder) Encode(frame Frame) error {
+	return e.send(frame.cptr)
+}
+
+// Flush drains the encoder and writes its remaining packets. No frames may be
+// encoded afterwards; the caller should then write the trailer.
+func (e *Encoder) Flush() error {
+	return e.send(nil)
+}
+
+// send feeds frame to the codec and writes every packet it produces. If the
+// codec refuses the frame until output is consumed, send drains the codec and
+// retries.
+func (e *Encoder) send(frame *C.AVFrame) error {
+	pkt, err := NewPacket()
+	if err != nil {
+		return err
+	}
+	defer pkt.Free()
+	for {
+		result := C.avcodec_send_frame(e.codec.cptr, frame)
+		if result < 0 && int(result) != ErrAgain.Code {
+			return newError("failed to send frame", int(result))
+		}
+		if err := e.receive(pkt); err != nil {
+			return err
+		}
+		if result == 0 {
+			return nil
+		}
+	}
+}
+
+// receive writes encoded packets until the codec needs more input or has been
+// fully drained.
+func (e *Encoder) receive(pkt Packet) error {
+	for {
+		result := C.avcodec_receive_packet(e.codec.cptr, pkt.cptr)
+		if int(result) == ErrAgain.Code || int(result) == ErrEOF.Code {
+			return nil
+		}
+		if result < 0 {
+			return newError("failed receiving packet", int(result))
+		}
+		pkt.RescaleTS(e.codec.TimeBase(), e.stream.TimeBase())
+		pkt.SetStreamIndex(e.stream.Index())
+		if err := e.format.WriteFrame(pkt); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package ffmpeg
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testWidth  = 64
+	testHeight = 48
+	testFrames = 10
+)
+
+// testLuma is the luma of every pixel of test frame i.
+func testLuma(i int) byte {
+	return byte(16 + 20*i)
+}
+
+// newTestFrame returns a flat YUV420P frame whose luma identifies it.
+func newTestFrame(t *testing.T, i int) Frame {
+	t.Helper()
+	f, err := NewVideoFrame(testWidth, testHeight, PixelFormatYUV420P)
+	if err != nil {
+		t.Fatalf("NewVideoFrame: %v", err)
+	}
+	for plane, value := range []byte{testLuma(i), 128, 128} {
+		rows := testHeight
+		if plane > 0 {
+			rows /= 2
+		}
+		n := rows * f.PlaneLinesize(plane)
+		data := (*[1 << 30]byte)(f.PlaneData(plane))[:n:n]
+		for j := range data {
+			data[j] = value
+		}
+	}
+	f.SetPTS(int64(i))
+	return f
+}
+
+// encodeTestFile records testFrames test frames to filename with the named
+// encoder at 25 frames per second.
+func encodeTestFile(t *testing.T, filename, encoder string) {
+	t.Helper()
+	codec, err := FindEncoderByName(encoder)
+	if err != nil {
+		t.Skipf("encoder %s not available: %v", encoder, err)
+	}
+	out, err := NewOutputFormatContext(filename, "" /*formatName*/)
+	if err != nil {
+		t.Fatalf("NewOutputFormatContext: %v", err)
+	}
+	defer out.Close()
+	codecCtx, err := codec.NewEncoderContext(EncoderParams{
+		Width:        testWidth,
+		Height:       testHeight,
+		PixelFormat:  PixelFormatYUV420P,
+		TimeBase:     Rational{Num: 1, Den: 25},
+		FrameRate:    Rational{Num: 25, Den: 1},
+		GlobalHeader: out.NeedsGlobalHeader(),
+	}, nil /*options*/)
+	if err != nil {
+		t.Fatalf("NewEncoderContext: %v", err)
+	}
+	defer codecCtx.Free()
+	stream, err := out.NewStream(codecCtx)
+	if err != nil {
+		t.Fatalf("NewStream: %v", err)
+	}
+	if err := out.WriteHeader(nil /*options*/); err != nil {
+		t.Fatalf("WriteHeader: %v", err)
+	}
+	enc := NewEncoder(out, codecCtx, stream)
+	for i := 0; i < testFrames; i++ {
+		f := newTestFrame(t, i)
+		err := enc.Encode(f)
+		f.Free()
+		if err != nil {
+			t.Fatalf("Encode(frame %d): %v", i, err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := out.WriteTrailer(); err != nil {
+		t.Fatalf("WriteTrailer: %v", err)
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		encoder  string
+		lossless bool
+	}{
+		{"rawvideo", true},
+		{"ffv1", true},
+		{"mpeg4", false},
+	} {
+		t.Run(test.encoder, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "test.mkv")
+			encodeTestFile(t, filename, test.encoder)
+
+			in, err := NewFormatContext(filename, InputFormat{}, nil /*options*/)
+			if err != nil {
+				t.Fatalf("NewFormatContext: %v", err)
+			}
+			defer in.Close()
+			if err := in.FindStreamInfo(); err != nil {
+				t.Fatalf("FindStreamInfo: %v", err)
+			}
+			stream, err := in.FindBestStream(MediaTypeVideo)
+			if err != nil {
+				t.Fatalf("FindBestStream: %v", err)
+			}
+			codec, err := FindDecoder(stream.Codecpar().CodecID())
+			if err != nil {
+				t.Fatalf("FindDecoder: %v", err)
+			}
+			codecCtx, err := codec.NewContext(stream.Codecpar(), nil /*options*/)
+			if err != nil {
+				t.Fatalf("NewContext: %v", err)
+			}
+			defer codecCtx.Free()
+
+			dec := NewDecoder(in, codecCtx, stream.Index())
+			frames := dec.Frames(context.Background())
+			if again := dec.Frames(context.Background()); again != frames {
+				t.Error("second call to Frames started another decode")
+			}
+			n := 0
+			for f := range frames {
+				if f.Width() != testWidth || f.Height() != testHeight {
+					t.Errorf("frame %d is %dx%d, want %dx%d", n, f.Width(), f.Height(), testWidth, testHeight)
+				}
+				pts := RescaleQ(f.PTS(), stream.TimeBase(), Rational{Num: 1, Den: 25})
+				if pts != int64(n) {
+					t.Errorf("frame %d has PTS %d in frame intervals, want %d", n, pts, n)
+				}
+				if test.lossless {
+					if got := *(*byte)(f.PlaneData(0)); got != testLuma(n) {
+						t.Errorf("frame %d has luma %d, want %d", n, got, testLuma(n))
+					}
+				}
+				f.Free()
+				n++
+			}
+			if err := dec.Err(); err != nil {
+				t.Errorf("Err() = %v, want nil at EOF", err)
+			}
+			if n != testFrames {
+				t.Errorf("decoded %d frames, want %d", n, testFrames)
+			}
+		})
+	}
+}
+
+func TestDecoderCancel(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.mkv")
+	encodeTestFile(t, filename, "rawvideo")
+	in, err := NewFormatContext(filename, InputFormat{}, nil /*options*/)
+	if err != nil {
+		t.Fatalf("NewFormatContext: %v", err)
+	}
+	defer in.Close()
+	stream, err := in.FindBestStream(MediaTypeVideo)
+	if err != nil {
+		t.Fatalf("FindBestStream: %v", err)
+	}
+	codec, err := FindDecoder(stream.Codecpar().CodecID())
+	if err != nil {
+		t.Fatalf("FindDecoder: %v", err)
+	}
+	codecCtx, err := codec.NewContext(stream.Codecpar(), nil /*options*/)
+	if err != nil {
+		t.Fatalf("NewContext: %v", err)
+	}
+	defer codecCtx.Free()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dec := NewDecoder(in, codecCtx, stream.Index())
+	frames := dec.Frames(ctx)
+	f, ok := <-frames
+	if !ok {
+		t.Fatalf("no frame decoded: %v", dec.Err())
+	}
+	f.Free()
+	cancel()
+	for f := range frames {
+		f.Free()
+	}
+	if err := dec.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Err() = %v, want %v", err, context.Canceled)
+	}
+}
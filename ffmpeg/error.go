@@ -0,0 +1,74 @@
+package ffmpeg
+
+/*
+  #include <errno.h>
+  #include <stdlib.h>
+  #include <libavutil/error.h>
+
+  static int averror(int e) { return AVERROR(e); }
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// Error wraps an AVERROR code returned by an ffmpeg function. Two Errors match
+// with errors.Is when their codes are equal, so a returned Error can be
+// compared against the sentinel values below.
+type Error struct {
+	// Code is the negative AVERROR value.
+	Code int
+	// Op describes the failed operation, e.g. "failed to read frame".
+	Op string
+}
+
+// Sentinel errors for the AVERROR codes callers commonly need to handle.
+var (
+	ErrAgain            = &Error{Code: int(C.averror(C.EAGAIN))}
+	ErrEOF              = &Error{Code: C.AVERROR_EOF}
+	ErrInvalidData      = &Error{Code: C.AVERROR_INVALIDDATA}
+	ErrInvalidArgument  = &Error{Code: int(C.averror(C.EINVAL))}
+	ErrNoMemory         = &Error{Code: int(C.averror(C.ENOMEM))}
+	ErrIO               = &Error{Code: int(C.averror(C.EIO))}
+	ErrBufferTooSmall   = &Error{Code: C.AVERROR_BUFFER_TOO_SMALL}
+	ErrBug              = &Error{Code: C.AVERROR_BUG}
+	ErrDecoderNotFound  = &Error{Code: C.AVERROR_DECODER_NOT_FOUND}
+	ErrDemuxerNotFound  = &Error{Code: C.AVERROR_DEMUXER_NOT_FOUND}
+	ErrEncoderNotFound  = &Error{Code: C.AVERROR_ENCODER_NOT_FOUND}
+	ErrExit             = &Error{Code: C.AVERROR_EXIT}
+	ErrExternal         = &Error{Code: C.AVERROR_EXTERNAL}
+	ErrMuxerNotFound    = &Error{Code: C.AVERROR_MUXER_NOT_FOUND}
+	ErrOptionNotFound   = &Error{Code: C.AVERROR_OPTION_NOT_FOUND}
+	ErrPatchWelcome     = &Error{Code: C.AVERROR_PATCHWELCOME}
+	ErrProtocolNotFound = &Error{Code: C.AVERROR_PROTOCOL_NOT_FOUND}
+	ErrStreamNotFound   = &Error{Code: C.AVERROR_STREAM_NOT_FOUND}
+	ErrUnknown          = &Error{Code: C.AVERROR_UNKNOWN}
+)
+
+// newError returns an Error describing the failed operation op.
+func newError(op string, code int) *Error {
+	return &Error{Code: code, Op: op}
+}
+
+func (e *Error) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("ffmpeg: [%d] %s", e.Code, getErrStr(C.int(e.Code)))
+	}
+	return fmt.Sprintf("ffmpeg: %s: [%d] %s", e.Op, e.Code, getErrStr(C.int(e.Code)))
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// getErrStr gets the corresponding error message for the given result code.
+func getErrStr(result C.int) string {
+	errStr := C.CString(strings.Repeat(" ", C.AV_ERROR_MAX_STRING_SIZE))
+	defer C.free(unsafe.Pointer(errStr))
+	C.av_strerror(result, errStr, C.AV_ERROR_MAX_STRING_SIZE)
+	return C.GoString(errStr)
+}
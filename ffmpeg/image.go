@@ -0,0 +1,85 @@
+package ffmpeg
+
+/*
+  #include <libavutil/frame.h>
+  #include <libavutil/pixfmt.h>
+*/
+import "C"
+import (
+	"image"
+	"unsafe"
+)
+
+// ToImage copies the frame out of C memory into a Go image. Full-range
+// (JPEG) planar YUV frames become an *image.YCbCr, GRAY8 frames an
+// *image.Gray and everything else, including limited-range YUV which Go's
+// YCbCr model cannot represent, is converted to an *image.NRGBA, since
+// ffmpeg's RGBA has straight alpha where Go's image.RGBA is premultiplied.
+func (f Frame) ToImage() (image.Image, error) {
+	r := image.Rect(0, 0, f.Width(), f.Height())
+	if ratio, ok := f.subsampleRatio(); ok {
+		img := image.NewYCbCr(r, ratio)
+		copyPlane(img.Y, img.YStride, f.PlaneData(0), f.PlaneLinesize(0))
+		copyPlane(img.Cb, img.CStride, f.PlaneData(1), f.PlaneLinesize(1))
+		copyPlane(img.Cr, img.CStride, f.PlaneData(2), f.PlaneLinesize(2))
+		return img, nil
+	}
+	switch f.Format() {
+	case PixelFormatGray8:
+		img := image.NewGray(r)
+		copyPlane(img.Pix, img.Stride, f.PlaneData(0), f.PlaneLinesize(0))
+		return img, nil
+	case PixelFormatRGBA:
+		img := image.NewNRGBA(r)
+		copyPlane(img.Pix, img.Stride, f.PlaneData(0), f.PlaneLinesize(0))
+		return img, nil
+	}
+
+	scaler, err := NewScaler(
+		f.Width(), f.Height(), f.Format(),
+		f.Width(), f.Height(), PixelFormatRGBA,
+		ScalePoint)
+	if err != nil {
+		return nil, err
+	}
+	defer scaler.Free()
+	rgba, err := scaler.Scale(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rgba.Free()
+	return rgba.ToImage()
+}
+
+// subsampleRatio returns the image.YCbCr subsample ratio matching the frame's
+// pixel format, if the frame can be copied into an image.YCbCr as is.
+func (f Frame) subsampleRatio() (image.YCbCrSubsampleRatio, bool) {
+	fullRange := f.cptr.color_range == C.AVCOL_RANGE_JPEG
+	switch f.Format() {
+	case PixelFormatYUVJ420P:
+		return image.YCbCrSubsampleRatio420, true
+	case PixelFormatYUVJ422P:
+		return image.YCbCrSubsampleRatio422, true
+	case PixelFormatYUVJ444P:
+		return image.YCbCrSubsampleRatio444, true
+	case PixelFormatYUV420P:
+		return image.YCbCrSubsampleRatio420, fullRange
+	case PixelFormatYUV422P:
+		return image.YCbCrSubsampleRatio422, fullRange
+	case PixelFormatYUV444P:
+		return image.YCbCrSubsampleRatio444, fullRange
+	}
+	return 0, false
+}
+
+// copyPlane copies len(dst)/dstStride rows of dstStride bytes from a C plane
+// with the given linesize. A negative linesize (bottom-up frame) is honored.
+func copyPlane(dst []byte, dstStride int, src unsafe.Pointer, srcStride int) {
+	if src == nil || dstStride == 0 {
+		return
+	}
+	for y := 0; y < len(dst)/dstStride; y++ {
+		row := unsafe.Pointer(uintptr(src) + uintptr(y*srcStride))
+		copy(dst[y*dstStride:(y+1)*dstStride], (*[1 << 30]byte)(row)[:dstStride:dstStride])
+	}
+}
@@ -0,0 +1,84 @@
+package ffmpeg
+
+/*
+  #include <stdlib.h>
+  #include <libavcodec/avcodec.h>
+  #include <libavformat/avformat.h>
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// NewOutputFormatContext wraps avformat_alloc_output_context2 and, unless the
+// muxer writes no file, avio_open. formatName selects the muxer, e.g.
+// "matroska"; if empty the muxer is guessed from the filename extension.
+func NewOutputFormatContext(filename, formatName string) (FormatContext, error) {
+	filenamecs := C.CString(filename)
+	defer C.free(unsafe.Pointer(filenamecs))
+	var formatNamecs *C.char
+	if formatName != "" {
+		formatNamecs = C.CString(formatName)
+		defer C.free(unsafe.Pointer(formatNamecs))
+	}
+	var ctxp *C.AVFormatContext
+	if result := C.avformat_alloc_output_context2(&ctxp, nil /*oformat*/, formatNamecs, filenamecs); result < 0 {
+		return FormatContext{}, newError(fmt.Sprintf("failed to create output context for %q", filename), int(result))
+	}
+	if ctxp.oformat.flags&C.AVFMT_NOFILE == 0 {
+		if result := C.avio_open(&ctxp.pb, filenamecs, C.AVIO_FLAG_WRITE); result < 0 {
+			C.avformat_free_context(ctxp)
+			return FormatContext{}, newError(fmt.Sprintf("failed to open %q for writing", filename), int(result))
+		}
+	}
+	return FormatContext{ctxp, filename, true}, nil
+}
+
+// NeedsGlobalHeader reports whether the output format wants codec headers in
+// the container rather than in the stream (AVFMT_GLOBALHEADER).
+func (ctx FormatContext) NeedsGlobalHeader() bool {
+	return ctx.output && ctx.cptr.oformat.flags&C.AVFMT_GLOBALHEADER != 0
+}
+
+// NewStream wraps avformat_new_stream and copies the parameters of the opened
+// encoder into the new stream.
+func (ctx FormatContext) NewStream(encoder CodecContext) (Stream, error) {
+	cptr := C.avformat_new_stream(ctx.cptr, nil /*codec*/)
+	if cptr == nil {
+		return Stream{}, newError("failed to create stream", ErrNoMemory.Code)
+	}
+	if result := C.avcodec_parameters_from_context(cptr.codecpar, encoder.cptr); result < 0 {
+		return Stream{}, newError("failed to copy encoder parameters to stream", int(result))
+	}
+	cptr.time_base = encoder.cptr.time_base
+	return Stream{cptr}, nil
+}
+
+// WriteHeader wraps avformat_write_header. The muxer may change the time base
+// of the streams. options may be nil; otherwise, on return it holds only the
+// options the muxer did not recognize.
+func (ctx FormatContext) WriteHeader(options *Dictionary) error {
+	if result := C.avformat_write_header(ctx.cptr, dictPtr(options)); result < 0 {
+		return newError("failed to write header", int(result))
+	}
+	return nil
+}
+
+// WriteFrame wraps av_interleaved_write_frame. The packet's timestamps must be
+// in the time base of its stream. The muxer takes ownership of the packet's
+// data, leaving p blank.
+func (ctx FormatContext) WriteFrame(p Packet) error {
+	if result := C.av_interleaved_write_frame(ctx.cptr, p.cptr); result < 0 {
+		return newError("failed to write frame", int(result))
+	}
+	return nil
+}
+
+// WriteTrailer wraps av_write_trailer.
+func (ctx FormatContext) WriteTrailer() error {
+	if result := C.av_write_trailer(ctx.cptr); result < 0 {
+		return newError("failed to write trailer", int(result))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package ffmpeg
+
+/*
+  #cgo pkg-config: libavutil
+  #include <libavutil/pixdesc.h>
+  #include <libavutil/pixfmt.h>
+  #include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// PixelFormat represents an AVPixelFormat.
+type PixelFormat C.enum_AVPixelFormat
+
+// Supported pixel formats. Packed RGB formats are named by their byte order in
+// memory, so PixelFormat0BGR stores X, B, G, R in consecutive bytes.
+const (
+	PixelFormatNone     = PixelFormat(C.AV_PIX_FMT_NONE)
+	PixelFormatYUV420P  = PixelFormat(C.AV_PIX_FMT_YUV420P)
+	PixelFormatYUV422P  = PixelFormat(C.AV_PIX_FMT_YUV422P)
+	PixelFormatYUV444P  = PixelFormat(C.AV_PIX_FMT_YUV444P)
+	PixelFormatYUVJ420P = PixelFormat(C.AV_PIX_FMT_YUVJ420P)
+	PixelFormatYUVJ422P = PixelFormat(C.AV_PIX_FMT_YUVJ422P)
+	PixelFormatYUVJ444P = PixelFormat(C.AV_PIX_FMT_YUVJ444P)
+	PixelFormatYUYV422  = PixelFormat(C.AV_PIX_FMT_YUYV422)
+	PixelFormatUYVY422  = PixelFormat(C.AV_PIX_FMT_UYVY422)
+	PixelFormatNV12     = PixelFormat(C.AV_PIX_FMT_NV12)
+	PixelFormatNV21     = PixelFormat(C.AV_PIX_FMT_NV21)
+	PixelFormatGray8    = PixelFormat(C.AV_PIX_FMT_GRAY8)
+	PixelFormatRGB24    = PixelFormat(C.AV_PIX_FMT_RGB24)
+	PixelFormatBGR24    = PixelFormat(C.AV_PIX_FMT_BGR24)
+	PixelFormatRGBA     = PixelFormat(C.AV_PIX_FMT_RGBA)
+	PixelFormatBGRA     = PixelFormat(C.AV_PIX_FMT_BGRA)
+	PixelFormatARGB     = PixelFormat(C.AV_PIX_FMT_ARGB)
+	PixelFormatABGR     = PixelFormat(C.AV_PIX_FMT_ABGR)
+	PixelFormatRGB0     = PixelFormat(C.AV_PIX_FMT_RGB0)
+	PixelFormatBGR0     = PixelFormat(C.AV_PIX_FMT_BGR0)
+	PixelFormat0RGB     = PixelFormat(C.AV_PIX_FMT_0RGB)
+	PixelFormat0BGR     = PixelFormat(C.AV_PIX_FMT_0BGR)
+	PixelFormatRGB565LE = PixelFormat(C.AV_PIX_FMT_RGB565LE)
+	PixelFormatBGR565LE = PixelFormat(C.AV_PIX_FMT_BGR565LE)
+)
+
+// PixelFormatByName wraps av_get_pix_fmt, returning PixelFormatNone for an
+// unknown name.
+func PixelFormatByName(name string) PixelFormat {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return PixelFormat(C.av_get_pix_fmt(cname))
+}
+
+// String wraps av_get_pix_fmt_name.
+func (f PixelFormat) String() string {
+	name := C.av_get_pix_fmt_name(C.enum_AVPixelFormat(f))
+	if name == nil {
+		return fmt.Sprintf("PixelFormat(%d)", int(f))
+	}
+	return C.GoString(name)
+}
@@ -0,0 +1,59 @@
+package ffmpeg
+
+import "sync/atomic"
+
+// FrameQueue is a bounded queue of frames between a producer, such as the
+// loop receiving from Decoder.Frames, and a consumer that may fall behind.
+// Push never blocks: when the queue is full the oldest frame is freed and
+// dropped, so the consumer always gets the most recent frames and a slow
+// consumer never stalls the producer.
+type FrameQueue struct {
+	frames  chan Frame
+	dropped int64
+}
+
+// NewFrameQueue returns a FrameQueue holding up to size frames.
+func NewFrameQueue(size int) *FrameQueue {
+	if size < 1 {
+		size = 1
+	}
+	return &FrameQueue{frames: make(chan Frame, size)}
+}
+
+// Push adds f to the queue, which takes ownership of it, dropping the oldest
+// frame if the queue is full. Push must only be called by one goroutine and
+// not after Close.
+func (q *FrameQueue) Push(f Frame) {
+	for {
+		select {
+		case q.frames <- f:
+			return
+		default:
+		}
+		// The consumer may take the oldest frame first, in which case the
+		// next attempt to add f succeeds.
+		select {
+		case old := <-q.frames:
+			old.Free()
+			atomic.AddInt64(&q.dropped, 1)
+		default:
+		}
+	}
+}
+
+// Close closes the channel returned by Frames once the queued frames have been
+// received. It must be called by the goroutine calling Push.
+func (q *FrameQueue) Close() {
+	close(q.frames)
+}
+
+// Frames returns the channel on which queued frames are delivered, oldest
+// first. The receiver owns every Frame it receives and must Free it.
+func (q *FrameQueue) Frames() <-chan Frame {
+	return q.frames
+}
+
+// Dropped returns the number of frames dropped because the queue was full.
+func (q *FrameQueue) Dropped() int64 {
+	return atomic.LoadInt64(&q.dropped)
+}
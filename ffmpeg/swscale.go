@@ -0,0 +1,101 @@
+package ffmpeg
+
+/*
+  #cgo pkg-config: libswscale
+  #include <libavutil/frame.h>
+  #include <libswscale/swscale.h>
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ScaleFlags selects the scaling algorithm used by a Scaler.
+type ScaleFlags int
+
+// Supported scaling algorithms.
+const (
+	ScaleFastBilinear = ScaleFlags(C.SWS_FAST_BILINEAR)
+	ScaleBilinear     = ScaleFlags(C.SWS_BILINEAR)
+	ScaleBicubic      = ScaleFlags(C.SWS_BICUBIC)
+	ScalePoint        = ScaleFlags(C.SWS_POINT)
+	ScaleArea         = ScaleFlags(C.SWS_AREA)
+)
+
+// frameAlign is the buffer alignment used for frames allocated by this
+// package.
+const frameAlign = 32
+
+// Scaler wraps a SwsContext that converts frames of one size and pixel format
+// into another.
+type Scaler struct {
+	cptr                *C.struct_SwsContext
+	srcWidth, srcHeight int
+	srcFormat           PixelFormat
+	dstWidth, dstHeight int
+	dstFormat           PixelFormat
+}
+
+// NewScaler wraps sws_getContext.
+func NewScaler(
+	srcWidth, srcHeight int, srcFormat PixelFormat,
+	dstWidth, dstHeight int, dstFormat PixelFormat,
+	flags ScaleFlags) (Scaler, error) {
+	cptr := C.sws_getContext(
+		C.int(srcWidth), C.int(srcHeight), C.enum_AVPixelFormat(srcFormat),
+		C.int(dstWidth), C.int(dstHeight), C.enum_AVPixelFormat(dstFormat),
+		C.int(flags), nil /*srcFilter*/, nil /*dstFilter*/, nil /*param*/)
+	if cptr == nil {
+		return Scaler{}, newError(
+			fmt.Sprintf("could not convert %dx%d %v to %dx%d %v",
+				srcWidth, srcHeight, srcFormat, dstWidth, dstHeight, dstFormat),
+			ErrInvalidArgument.Code)
+	}
+	return Scaler{cptr, srcWidth, srcHeight, srcFormat, dstWidth, dstHeight, dstFormat}, nil
+}
+
+// Free wraps sws_freeContext.
+func (s Scaler) Free() {
+	C.sws_freeContext(s.cptr)
+}
+
+// Scale converts src into a newly allocated frame of the destination size and
+// pixel format. The caller must Free the returned frame.
+func (s Scaler) Scale(src Frame) (Frame, error) {
+	dst, err := NewVideoFrame(s.dstWidth, s.dstHeight, s.dstFormat)
+	if err != nil {
+		return Frame{}, err
+	}
+	if err := s.ScaleInto(dst, src); err != nil {
+		dst.Free()
+		return Frame{}, err
+	}
+	return dst, nil
+}
+
+// ScaleInto wraps sws_scale, converting src into the already allocated frame
+// dst.
+func (s Scaler) ScaleInto(dst, src Frame) error {
+	if int(src.cptr.width) != s.srcWidth || int(src.cptr.height) != s.srcHeight ||
+		PixelFormat(src.cptr.format) != s.srcFormat {
+		return newError(
+			fmt.Sprintf("scaler expects %dx%d %v, got %dx%d %v",
+				s.srcWidth, s.srcHeight, s.srcFormat,
+				src.cptr.width, src.cptr.height, PixelFormat(src.cptr.format)),
+			ErrInvalidArgument.Code)
+	}
+	result := C.sws_scale(
+		s.cptr,
+		(**C.uint8_t)(unsafe.Pointer(&src.cptr.data[0])),
+		&src.cptr.linesize[0],
+		0, /*srcSliceY*/
+		C.int(s.srcHeight),
+		(**C.uint8_t)(unsafe.Pointer(&dst.cptr.data[0])),
+		&dst.cptr.linesize[0])
+	if result < 0 {
+		return newError("failed to scale frame", int(result))
+	}
+	dst.cptr.pts = src.cptr.pts
+	return nil
+}
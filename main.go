@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"runtime"
 	"sort"
 
 	_ "image/jpeg"
@@ -12,76 +13,35 @@ import (
 	"./bcmhost"
 	"./egl"
 	"./openvg"
+	"./screen"
 	"github.com/blackjack/webcam"
 )
 
+func init() {
+	// The EGL context is current only on the OS thread that made it current,
+	// and main draws every frame with it.
+	runtime.LockOSThread()
+}
+
 func main() {
-	bcmhost.Init()
-	defer bcmhost.Deinit()
-	w, h, err := bcmhost.GraphicsGetDisplaySize(bcmhost.DispmanxIDMainLcd)
+	scr, err := screen.Open(screen.Options{
+		Display: bcmhost.DispmanxIDMainLcd,
+		Layer:   1,
+	})
 	if err != nil {
-		log.Fatalf("bcmhost: %v", err)
+		log.Fatal(err)
 	}
+	defer scr.Close()
+	w, h := scr.Width(), scr.Height()
 	fmt.Printf("Display size: %d %d\n", w, h)
-
-	display, err := bcmhost.DispmanxDisplayOpen(bcmhost.DispmanxIDMainLcd)
-	if err != nil {
-		log.Fatalf("bcmhost: %v", err)
-	}
-	defer display.Close()
-
-	update, err := display.UpdateStart(0 /*priority*/)
-	if err != nil {
-		log.Fatalf("bcmhost: %v", err)
-	}
-	dest := bcmhost.Rect{0, 0, w, h}
-	src := bcmhost.Rect{0, 0, w << 16, h << 16}
-	element, err := update.ElementAdd(
-		1, /*layer*/
-		dest,
-		bcmhost.DispmanxDefaultResource,
-		src,
-		bcmhost.DispmanxProtectionNone)
-	if err != nil {
-		log.Fatalf("bcmhost: %v", err)
+	if version, err := scr.Display().QueryString(egl.Version); err == nil {
+		fmt.Printf("EGL version: %s\n", version)
 	}
 
-	eglDisplay, err := egl.GetDisplay(egl.DefaultDisplay)
-	if err != nil {
-		log.Fatalf("egl: %v", err)
-	}
-	version, err := eglDisplay.Initialize()
-	if err != nil {
-		log.Fatalf("egl: %v", err)
+	openvg.SetClearColor(1, 1, 1, 1)
+	if err := openvg.Clear(0, 0, w, h); err != nil {
+		log.Fatalf("openvg: %v", err)
 	}
-	fmt.Printf("EGL version: %s\n", version)
-	defer eglDisplay.Terminate()
-	egl.BindAPI(egl.APIOpenVG)
-	config, err := eglDisplay.ChooseConfig()
-	if err != nil {
-		log.Fatalf("egl: %v", err)
-	}
-	window := bcmhost.NewDispmanxWindow(element, w, h)
-	surface, err := eglDisplay.CreateWindowSurface(config, window)
-	if err != nil {
-		log.Fatalf("egl: %v", err)
-	}
-	ctx, err := eglDisplay.CreateContext(config)
-	if err != nil {
-		log.Fatalf("egl: %v", err)
-	}
-	err = eglDisplay.MakeCurrent(surface, ctx)
-	if err != nil {
-		log.Fatalf("egl: %v", err)
-	}
-	// TODO(robert): Defer release the context.
-	err = update.UpdateSubmit()
-	if err != nil {
-		log.Fatalf("bcmhost: %v", err)
-	}
-
-	openvg.SetClearColor()
-	openvg.Clear(0, 0, w, h)
 
 	cam, err := webcam.Open("/dev/video0") // Open webcam
 	if err != nil {
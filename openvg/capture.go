@@ -0,0 +1,32 @@
+package openvg
+
+import (
+	"image/png"
+	"io"
+	"os"
+)
+
+// WritePNG encodes the width x height area of the drawing surface at (x, y)
+// as a PNG. The back buffer is undefined after eglSwapBuffers, so capture a
+// frame after drawing it and before swapping.
+func WritePNG(w io.Writer, x, y, width, height int) error {
+	img, err := ReadPixels(x, y, width, height)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// SavePNG writes the width x height area of the drawing surface at (x, y) to
+// a PNG file.
+func SavePNG(filename string, x, y, width, height int) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := WritePNG(f, x, y, width, height); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,64 @@
+package openvg
+
+// PixelRect is a rectangle of whole pixels in surface coordinates, whose
+// origin is the bottom-left corner of the surface. It has the fields of
+// bcmhost.Rect, so either converts to the other.
+type PixelRect struct {
+	X, Y, Width, Height int
+}
+
+// MaskOperation represents a VGMaskOperation, which selects how a source
+// combines with the drawing surface mask.
+type MaskOperation int
+
+const (
+	MaskClear     = MaskOperation(0x1500)
+	MaskFill      = MaskOperation(0x1501)
+	MaskSet       = MaskOperation(0x1502)
+	MaskUnion     = MaskOperation(0x1503)
+	MaskIntersect = MaskOperation(0x1504)
+	MaskSubtract  = MaskOperation(0x1505)
+)
+
+func (op MaskOperation) valid() bool {
+	return op >= MaskClear && op <= MaskSubtract
+}
+
+// SetScissorRects sets ParamScissorRects. While scissoring is enabled, only
+// pixels inside one of the rectangles are drawn or cleared. Rectangles beyond
+// ParamMaxScissorRects are ignored.
+func SetScissorRects(rects []PixelRect) error {
+	values := make([]float32, 0, 4*len(rects))
+	for _, r := range rects {
+		values = append(values, float32(r.X), float32(r.Y), float32(r.Width), float32(r.Height))
+	}
+	return Setfv(ParamScissorRects, values)
+}
+
+// ScissorRects returns ParamScissorRects.
+func ScissorRects() []PixelRect {
+	values := Getfv(ParamScissorRects)
+	rects := make([]PixelRect, 0, len(values)/4)
+	for i := 0; i+4 <= len(values); i += 4 {
+		rects = append(rects, PixelRect{int(values[i]), int(values[i+1]), int(values[i+2]), int(values[i+3])})
+	}
+	return rects
+}
+
+// SetScissoring sets ParamScissoring.
+func SetScissoring(enabled bool) error {
+	return Seti(ParamScissoring, boolParam(enabled))
+}
+
+// SetMasking sets ParamMasking. While masking is enabled, drawing is weighted
+// by the drawing surface mask.
+func SetMasking(enabled bool) error {
+	return Seti(ParamMasking, boolParam(enabled))
+}
+
+func boolParam(v bool) int {
+	if v {
+		return True
+	}
+	return False
+}
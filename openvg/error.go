@@ -0,0 +1,138 @@
+package openvg
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+)
+
+// ErrorCode represents a VGErrorCode.
+type ErrorCode int
+
+// Values from VGErrorCode, and from VGUErrorCode for errors that only VGU
+// reports.
+const (
+	NoError                     = ErrorCode(0)
+	BadHandleError              = ErrorCode(0x1000)
+	IllegalArgumentError        = ErrorCode(0x1001)
+	OutOfMemoryError            = ErrorCode(0x1002)
+	PathCapabilityError         = ErrorCode(0x1003)
+	UnsupportedImageFormatError = ErrorCode(0x1004)
+	UnsupportedPathFormatError  = ErrorCode(0x1005)
+	ImageInUseError             = ErrorCode(0x1006)
+	NoContextError              = ErrorCode(0x1007)
+	BadWarpError                = ErrorCode(0xF004)
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	NoError:                     "VG_NO_ERROR",
+	BadHandleError:              "VG_BAD_HANDLE_ERROR",
+	IllegalArgumentError:        "VG_ILLEGAL_ARGUMENT_ERROR",
+	OutOfMemoryError:            "VG_OUT_OF_MEMORY_ERROR",
+	PathCapabilityError:         "VG_PATH_CAPABILITY_ERROR",
+	UnsupportedImageFormatError: "VG_UNSUPPORTED_IMAGE_FORMAT_ERROR",
+	UnsupportedPathFormatError:  "VG_UNSUPPORTED_PATH_FORMAT_ERROR",
+	ImageInUseError:             "VG_IMAGE_IN_USE_ERROR",
+	NoContextError:              "VG_NO_CONTEXT_ERROR",
+	BadWarpError:                "VGU_BAD_WARP_ERROR",
+}
+
+func (c ErrorCode) String() string {
+	if name, ok := errorCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("VGErrorCode(%#x)", int(c))
+}
+
+// Error wraps an error code reported by vgGetError or a VGU function. Two
+// Errors match with errors.Is when their codes are equal, so a returned Error
+// can be compared against the sentinel values below.
+type Error struct {
+	Code ErrorCode
+	// Op describes the failed operation, e.g. "failed to draw path".
+	Op string
+	// Site is the file:line of the call into this package that failed. It is
+	// only recorded in debug mode; see SetDebug.
+	Site string
+}
+
+// Sentinel errors for each error code.
+var (
+	ErrBadHandle              = &Error{Code: BadHandleError}
+	ErrIllegalArgument        = &Error{Code: IllegalArgumentError}
+	ErrOutOfMemory            = &Error{Code: OutOfMemoryError}
+	ErrPathCapability         = &Error{Code: PathCapabilityError}
+	ErrUnsupportedImageFormat = &Error{Code: UnsupportedImageFormatError}
+	ErrUnsupportedPathFormat  = &Error{Code: UnsupportedPathFormatError}
+	ErrImageInUse             = &Error{Code: ImageInUseError}
+	ErrNoContext              = &Error{Code: NoContextError}
+	ErrBadWarp                = &Error{Code: BadWarpError}
+)
+
+// newError returns an Error describing the failed operation op.
+func newError(op string, code ErrorCode) *Error {
+	e := &Error{Code: code, Op: op}
+	if debugHandler != nil {
+		e.Site = callSite()
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	msg := "openvg: " + e.Code.String()
+	if e.Op != "" {
+		msg = "openvg: " + e.Op + ": " + e.Code.String()
+	}
+	if e.Site != "" {
+		msg += " (at " + e.Site + ")"
+	}
+	return msg
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// debugHandler receives the errors of calls without an error result in debug
+// mode; nil disables debug mode.
+var debugHandler func(*Error)
+
+// SetDebug enables the checked-call mode when handler is not nil. In this mode
+// every call checks vgGetError, even those without an error result, whose
+// errors are passed to handler instead; and errors record the call site that
+// failed. Checking after every call stalls the GPU pipeline, so leave it off
+// in production. Pass nil to disable it.
+func SetDebug(handler func(*Error)) {
+	debugHandler = handler
+}
+
+// report passes the error of a call without an error result to the debug
+// handler, if debug mode is enabled.
+func report(op string, code ErrorCode) {
+	if debugHandler != nil {
+		debugHandler(newError(op, code))
+	}
+}
+
+// LogErrors is a debug handler that logs each error.
+func LogErrors(err *Error) {
+	log.Print(err)
+}
+
+// callSite returns the file:line of the innermost caller outside this package.
+func callSite() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		f, more := frames.Next()
+		if !strings.Contains(f.Function, "/openvg.") && !strings.HasPrefix(f.Function, "openvg.") {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
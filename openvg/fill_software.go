@@ -0,0 +1,412 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"math"
+	"sort"
+)
+
+// pt is a point in user or surface coordinates.
+type pt struct {
+	x, y float32
+}
+
+func (p pt) add(q pt) pt           { return pt{p.x + q.x, p.y + q.y} }
+func (p pt) sub(q pt) pt           { return pt{p.x - q.x, p.y - q.y} }
+func (p pt) mul(k float32) pt      { return pt{p.x * k, p.y * k} }
+func (p pt) cross(q pt) float32    { return p.x*q.y - p.y*q.x }
+func (p pt) length() float32       { return float32(math.Hypot(float64(p.x), float64(p.y))) }
+func (p pt) transform(m Matrix) pt { x, y := m.Apply(p.x, p.y); return pt{x, y} }
+
+// normal returns the unit normal of p rotated 90 degrees counterclockwise.
+func (p pt) normal() pt {
+	l := p.length()
+	if l == 0 {
+		return pt{}
+	}
+	return pt{-p.y / l, p.x / l}
+}
+
+// polyline is a flattened subpath.
+type polyline struct {
+	pts    []pt
+	closed bool
+}
+
+// flatten converts d into polylines in user coordinates. Curves are split
+// finely enough that, once transformed by m, no piece is longer than a few
+// pixels.
+func (d *PathData) flatten(m Matrix) []polyline {
+	var lines []polyline
+	var cur polyline
+	var start, last pt
+	flush := func() {
+		if len(cur.pts) > 0 {
+			lines = append(lines, cur)
+		}
+		cur = polyline{}
+	}
+	pieces := func(ctrl ...pt) int {
+		var l float32
+		for i := 1; i < len(ctrl); i++ {
+			l += ctrl[i].transform(m).sub(ctrl[i-1].transform(m)).length()
+		}
+		n := int(math.Ceil(float64(l) / 3))
+		if n < 1 {
+			return 1
+		}
+		if n > 256 {
+			return 256
+		}
+		return n
+	}
+	c := d.coords
+	for _, seg := range d.segments {
+		args := c[:segCoords[seg]]
+		c = c[segCoords[seg]:]
+		switch seg {
+		case segMove:
+			flush()
+			start = pt{args[0], args[1]}
+			last = start
+			cur.pts = append(cur.pts, start)
+		case segLine:
+			if len(cur.pts) == 0 {
+				cur.pts = append(cur.pts, last)
+			}
+			last = pt{args[0], args[1]}
+			cur.pts = append(cur.pts, last)
+		case segQuad:
+			if len(cur.pts) == 0 {
+				cur.pts = append(cur.pts, last)
+			}
+			p0, p1, p2 := last, pt{args[0], args[1]}, pt{args[2], args[3]}
+			n := pieces(p0, p1, p2)
+			for i := 1; i <= n; i++ {
+				t := float32(i) / float32(n)
+				u := 1 - t
+				cur.pts = append(cur.pts, p0.mul(u*u).add(p1.mul(2*u*t)).add(p2.mul(t*t)))
+			}
+			last = p2
+		case segCubic:
+			if len(cur.pts) == 0 {
+				cur.pts = append(cur.pts, last)
+			}
+			p0, p1, p2, p3 := last, pt{args[0], args[1]}, pt{args[2], args[3]}, pt{args[4], args[5]}
+			n := pieces(p0, p1, p2, p3)
+			for i := 1; i <= n; i++ {
+				t := float32(i) / float32(n)
+				u := 1 - t
+				cur.pts = append(cur.pts,
+					p0.mul(u*u*u).add(p1.mul(3*u*u*t)).add(p2.mul(3*u*t*t)).add(p3.mul(t*t*t)))
+			}
+			last = p3
+		case segClose:
+			if len(cur.pts) > 0 {
+				cur.closed = true
+				flush()
+			}
+			last = start
+		}
+	}
+	flush()
+	return lines
+}
+
+// edge is a non-horizontal polygon edge in top-down surface coordinates.
+type edge struct {
+	x0, y0, x1, y1 float32
+	dir            int
+}
+
+// rasterize scan converts the polygons, given in top-down surface
+// coordinates, and calls plot with the coverage of every touched pixel. When
+// antialiasing, each pixel row is sampled on several sub-scanlines and spans
+// are weighted by their exact horizontal overlap.
+func rasterize(polys [][]pt, rule FillRule, antialias bool, width, height int, plot func(x, y int, coverage float32)) {
+	var edges []edge
+	minY, maxY := float32(math.Inf(1)), float32(math.Inf(-1))
+	minX, maxX := float32(math.Inf(1)), float32(math.Inf(-1))
+	for _, poly := range polys {
+		for i := range poly {
+			a, b := poly[i], poly[(i+1)%len(poly)]
+			minX = float32(math.Min(float64(minX), float64(a.x)))
+			maxX = float32(math.Max(float64(maxX), float64(a.x)))
+			if a.y == b.y {
+				continue
+			}
+			e := edge{a.x, a.y, b.x, b.y, 1}
+			if a.y > b.y {
+				e = edge{b.x, b.y, a.x, a.y, -1}
+			}
+			edges = append(edges, e)
+			minY = float32(math.Min(float64(minY), float64(e.y0)))
+			maxY = float32(math.Max(float64(maxY), float64(e.y1)))
+		}
+	}
+	if len(edges) == 0 {
+		return
+	}
+	x0 := clampInt(int(math.Floor(float64(minX))), 0, width)
+	x1 := clampInt(int(math.Ceil(float64(maxX))), 0, width)
+	y0 := clampInt(int(math.Floor(float64(minY))), 0, height)
+	y1 := clampInt(int(math.Ceil(float64(maxY))), 0, height)
+	if x0 >= x1 {
+		return
+	}
+	samples := 1
+	if antialias {
+		samples = 4
+	}
+	acc := make([]float32, x1-x0)
+	type crossing struct {
+		x   float32
+		dir int
+	}
+	var xs []crossing
+	for y := y0; y < y1; y++ {
+		for i := range acc {
+			acc[i] = 0
+		}
+		for k := 0; k < samples; k++ {
+			sy := float32(y) + (float32(k)+0.5)/float32(samples)
+			xs = xs[:0]
+			for _, e := range edges {
+				if sy < e.y0 || sy >= e.y1 {
+					continue
+				}
+				t := (sy - e.y0) / (e.y1 - e.y0)
+				xs = append(xs, crossing{e.x0 + t*(e.x1-e.x0), e.dir})
+			}
+			sort.Slice(xs, func(i, j int) bool { return xs[i].x < xs[j].x })
+			winding := 0
+			for i, c := range xs {
+				winding += c.dir
+				inside := winding != 0
+				if rule == FillRuleEvenOdd {
+					inside = winding%2 != 0
+				}
+				if !inside || i+1 >= len(xs) {
+					continue
+				}
+				addSpan(acc, x0, c.x, xs[i+1].x, 1/float32(samples), antialias)
+			}
+		}
+		for i, c := range acc {
+			if c > 0 {
+				plot(x0+i, y, float32(math.Min(float64(c), 1)))
+			}
+		}
+	}
+}
+
+// addSpan adds weight times the coverage of [a, b) to the pixels of acc,
+// which start at x0. Without antialiasing a pixel is covered if its center is.
+func addSpan(acc []float32, x0 int, a, b, weight float32, antialias bool) {
+	if !antialias {
+		first := clampInt(int(math.Ceil(float64(a-0.5))), x0, x0+len(acc))
+		last := clampInt(int(math.Ceil(float64(b-0.5))), x0, x0+len(acc))
+		for x := first; x < last; x++ {
+			acc[x-x0] += weight
+		}
+		return
+	}
+	first := clampInt(int(math.Floor(float64(a))), x0, x0+len(acc))
+	last := clampInt(int(math.Ceil(float64(b))), x0, x0+len(acc))
+	for x := first; x < last; x++ {
+		overlap := float32(math.Min(float64(b), float64(x+1)) - math.Max(float64(a), float64(x)))
+		if overlap > 0 {
+			acc[x-x0] += overlap * weight
+		}
+	}
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// strokeOutline returns polygons whose nonzero union is the stroke of lines
+// with the current stroke parameters, in user coordinates.
+func strokeOutline(lines []polyline) [][]pt {
+	hw := Getf(ParamStrokeLineWidth) / 2
+	if hw <= 0 {
+		return nil
+	}
+	capStyle := CapStyle(Geti(ParamStrokeCapStyle))
+	join := JoinStyle(Geti(ParamStrokeJoinStyle))
+	miterLimit := Getf(ParamStrokeMiterLimit)
+	var polys [][]pt
+	emit := func(poly ...pt) {
+		// Orient every piece counterclockwise so their union fills with the
+		// nonzero rule.
+		var area float32
+		for i := range poly {
+			area += poly[i].cross(poly[(i+1)%len(poly)])
+		}
+		if area < 0 {
+			for i, j := 0, len(poly)-1; i < j; i, j = i+1, j-1 {
+				poly[i], poly[j] = poly[j], poly[i]
+			}
+		}
+		polys = append(polys, poly)
+	}
+	circle := func(c pt) {
+		const n = 32
+		poly := make([]pt, n)
+		for i := range poly {
+			sin, cos := math.Sincos(2 * math.Pi * float64(i) / n)
+			poly[i] = pt{c.x + hw*float32(cos), c.y + hw*float32(sin)}
+		}
+		emit(poly...)
+	}
+	endCap := func(p, dir pt) {
+		switch capStyle {
+		case CapRound:
+			circle(p)
+		case CapSquare:
+			n := dir.normal().mul(hw)
+			ext := pt{dir.x, dir.y}.mul(hw / dir.length())
+			emit(p.add(n), p.add(n).add(ext), p.sub(n).add(ext), p.sub(n))
+		}
+	}
+
+	for _, line := range dash(lines) {
+		pts := dedupe(line.pts)
+		if len(pts) == 1 {
+			// A zero-length subpath only gets caps, oriented along the x axis.
+			endCap(pts[0], pt{1, 0})
+			endCap(pts[0], pt{-1, 0})
+			continue
+		}
+		if line.closed && pts[0] != pts[len(pts)-1] {
+			pts = append(pts, pts[0])
+		}
+		for i := 0; i+1 < len(pts); i++ {
+			n := pts[i+1].sub(pts[i]).normal().mul(hw)
+			emit(pts[i].add(n), pts[i+1].add(n), pts[i+1].sub(n), pts[i].sub(n))
+		}
+		joinAt := func(prev, v, next pt) {
+			d0, d1 := v.sub(prev), next.sub(v)
+			turn := d0.cross(d1)
+			if turn == 0 {
+				return
+			}
+			n0, n1 := d0.normal().mul(hw), d1.normal().mul(hw)
+			if turn > 0 {
+				// Turning left: the outer side is on the right.
+				n0, n1 = n0.mul(-1), n1.mul(-1)
+			}
+			switch join {
+			case JoinRound:
+				circle(v)
+			case JoinMiter:
+				cos := (d0.x*d1.x + d0.y*d1.y) / (d0.length() * d1.length())
+				cosHalf := float32(math.Sqrt(float64((1 + cos) / 2)))
+				if cosHalf > 0 && 1/cosHalf <= miterLimit {
+					bisector := n0.add(n1)
+					tip := v.add(bisector.mul(hw / (cosHalf * bisector.length())))
+					emit(v, v.add(n0), tip, v.add(n1))
+					return
+				}
+				emit(v, v.add(n0), v.add(n1))
+			default:
+				emit(v, v.add(n0), v.add(n1))
+			}
+		}
+		for i := 1; i+1 < len(pts); i++ {
+			joinAt(pts[i-1], pts[i], pts[i+1])
+		}
+		if line.closed {
+			joinAt(pts[len(pts)-2], pts[0], pts[1])
+		} else {
+			endCap(pts[0], pts[0].sub(pts[1]))
+			endCap(pts[len(pts)-1], pts[len(pts)-1].sub(pts[len(pts)-2]))
+		}
+	}
+	return polys
+}
+
+// dedupe drops consecutive duplicate points.
+func dedupe(pts []pt) []pt {
+	out := pts[:1:1]
+	for _, p := range pts[1:] {
+		if p != out[len(out)-1] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// dash splits lines according to ParamStrokeDashPattern and
+// ParamStrokeDashPhase. An empty pattern leaves lines unchanged.
+func dash(lines []polyline) []polyline {
+	pattern := Getfv(ParamStrokeDashPattern)
+	if len(pattern)%2 == 1 {
+		pattern = pattern[:len(pattern)-1]
+	}
+	var total float32
+	for i, v := range pattern {
+		if v < 0 {
+			pattern[i] = 0
+		}
+		total += pattern[i]
+	}
+	if len(pattern) == 0 || total <= 0 {
+		return lines
+	}
+	phase := float32(math.Mod(float64(Getf(ParamStrokeDashPhase)), float64(total)))
+	if phase < 0 {
+		phase += total
+	}
+
+	var out []polyline
+	for _, line := range lines {
+		pts := line.pts
+		if line.closed {
+			pts = append(append([]pt(nil), pts...), pts[0])
+		}
+		// Find the dash the phase falls into.
+		i, left := 0, pattern[0]-phase
+		for left <= 0 {
+			i = (i + 1) % len(pattern)
+			left += pattern[i]
+		}
+		var cur []pt
+		if i%2 == 0 {
+			cur = []pt{pts[0]}
+		}
+		for j := 0; j+1 < len(pts); j++ {
+			a, b := pts[j], pts[j+1]
+			segLen := b.sub(a).length()
+			pos := float32(0)
+			for segLen-pos > left {
+				pos += left
+				p := a.add(b.sub(a).mul(pos / segLen))
+				if i%2 == 0 {
+					out = append(out, polyline{pts: append(cur, p)})
+					cur = nil
+				} else {
+					cur = []pt{p}
+				}
+				i = (i + 1) % len(pattern)
+				left = pattern[i]
+			}
+			left -= segLen - pos
+			if i%2 == 0 {
+				cur = append(cur, b)
+			}
+		}
+		if i%2 == 0 && len(cur) > 1 {
+			out = append(out, polyline{pts: cur})
+		}
+	}
+	return out
+}
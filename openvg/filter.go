@@ -0,0 +1,107 @@
+//go:build !software
+// +build !software
+
+package openvg
+
+// #include "VG/openvg.h"
+import "C"
+import (
+	"image/color"
+	"unsafe"
+)
+
+// The filters read src and write the area it shares with dst, both anchored
+// at their bottom-left corners. dst and src must be different images. Only the
+// channels in ParamFilterChannelMask are written.
+
+// ColorMatrix wraps vgColorMatrix.
+func (dst Image) ColorMatrix(src Image, m ColorMatrix) error {
+	v := m.vgMatrix()
+	C.vgColorMatrix(dst.handle, src.handle, (*C.VGfloat)(unsafe.Pointer(&v[0])))
+	return checkError("failed to apply color matrix")
+}
+
+// Convolve wraps vgConvolve. Pixels outside src are read according to tiling.
+func (dst Image) Convolve(src Image, k Kernel, tiling TilingMode) error {
+	if !k.valid() {
+		return newError("failed to convolve", IllegalArgumentError)
+	}
+	values, shiftX, shiftY := k.vgKernel()
+	C.vgConvolve(
+		dst.handle,
+		src.handle,
+		C.VGint(k.Width),
+		C.VGint(k.Height),
+		C.VGint(shiftX),
+		C.VGint(shiftY),
+		(*C.VGshort)(unsafe.Pointer(&values[0])),
+		C.VGfloat(kernelScale(k.Scale)),
+		C.VGfloat(k.Bias),
+		C.VGTilingMode(tiling),
+	)
+	return checkError("failed to convolve")
+}
+
+// SeparableConvolve wraps vgSeparableConvolve. Pixels outside src are read
+// according to tiling.
+func (dst Image) SeparableConvolve(src Image, k SeparableKernel, tiling TilingMode) error {
+	if !k.valid() {
+		return newError("failed to convolve", IllegalArgumentError)
+	}
+	x, y, shiftX, shiftY := k.vgKernels()
+	C.vgSeparableConvolve(
+		dst.handle,
+		src.handle,
+		C.VGint(len(x)),
+		C.VGint(len(y)),
+		C.VGint(shiftX),
+		C.VGint(shiftY),
+		(*C.VGshort)(unsafe.Pointer(&x[0])),
+		(*C.VGshort)(unsafe.Pointer(&y[0])),
+		C.VGfloat(kernelScale(k.Scale)),
+		C.VGfloat(k.Bias),
+		C.VGTilingMode(tiling),
+	)
+	return checkError("failed to convolve")
+}
+
+// GaussianBlur wraps vgGaussianBlur. Pixels outside src are read according to
+// tiling.
+func (dst Image) GaussianBlur(src Image, stdDevX, stdDevY float32, tiling TilingMode) error {
+	C.vgGaussianBlur(dst.handle, src.handle, C.VGfloat(stdDevX), C.VGfloat(stdDevY), C.VGTilingMode(tiling))
+	return checkError("failed to blur")
+}
+
+// Lookup wraps vgLookup, mapping each channel of src through its table. The
+// tables produce non-linear, non-premultiplied values.
+func (dst Image) Lookup(src Image, red, green, blue, alpha LookupTable) error {
+	C.vgLookup(
+		dst.handle,
+		src.handle,
+		(*C.VGubyte)(&red[0]),
+		(*C.VGubyte)(&green[0]),
+		(*C.VGubyte)(&blue[0]),
+		(*C.VGubyte)(&alpha[0]),
+		C.VG_FALSE, /*outputLinear*/
+		C.VG_FALSE, /*outputPremultiplied*/
+	)
+	return checkError("failed to apply lookup")
+}
+
+// LookupSingle wraps vgLookupSingle, mapping one channel of src to whole
+// non-premultiplied colors.
+func (dst Image) LookupSingle(src Image, channel ImageChannel, lut *[256]color.NRGBA) error {
+	var values [256]C.VGuint
+	for i, c := range lut {
+		values[i] = C.VGuint(c.R)<<24 | C.VGuint(c.G)<<16 | C.VGuint(c.B)<<8 | C.VGuint(c.A)
+	}
+	C.vgLookupSingle(
+		dst.handle,
+		src.handle,
+		&values[0],
+		C.VGImageChannel(channel),
+		C.VG_FALSE, /*outputLinear*/
+		C.VG_FALSE, /*outputPremultiplied*/
+	)
+	return checkError("failed to apply lookup")
+}
@@ -0,0 +1,398 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"image/color"
+	"math"
+)
+
+// The filters read src and write the area it shares with dst, both anchored
+// at their bottom-left corners. dst and src must be different images. Only the
+// channels in ParamFilterChannelMask are written.
+
+// ColorMatrix wraps vgColorMatrix.
+func (dst Image) ColorMatrix(src Image, m ColorMatrix) error {
+	s, err := newFilterSource("apply color matrix", dst, src, TilePad)
+	if err != nil {
+		return err
+	}
+	s.write(dst, s.format, func(x, y int) [4]float32 {
+		in := s.at(x, y)
+		var out [4]float32
+		for row := range m {
+			out[row] = m[row][4]
+			for col := 0; col < 4; col++ {
+				out[row] += m[row][col] * in[col]
+			}
+		}
+		return out
+	})
+	return nil
+}
+
+// Convolve wraps vgConvolve. Pixels outside src are read according to tiling.
+func (dst Image) Convolve(src Image, k Kernel, tiling TilingMode) error {
+	if !k.valid() || k.Width > Geti(ParamMaxKernelSize) || k.Height > Geti(ParamMaxKernelSize) {
+		return newError("failed to convolve", IllegalArgumentError)
+	}
+	s, err := newFilterSource("convolve", dst, src, tiling)
+	if err != nil {
+		return err
+	}
+	scale := kernelScale(k.Scale)
+	s.write(dst, s.format, func(x, y int) [4]float32 {
+		var sum [4]float32
+		for r := 0; r < k.Height; r++ {
+			for c := 0; c < k.Width; c++ {
+				w := float32(k.Values[r*k.Width+c])
+				in := s.at(x+c-k.Width/2, y-(r-k.Height/2))
+				for i := range sum {
+					sum[i] += w * in[i]
+				}
+			}
+		}
+		for i := range sum {
+			sum[i] = sum[i]*scale + k.Bias
+		}
+		return sum
+	})
+	return nil
+}
+
+// SeparableConvolve wraps vgSeparableConvolve. Pixels outside src are read
+// according to tiling.
+func (dst Image) SeparableConvolve(src Image, k SeparableKernel, tiling TilingMode) error {
+	max := Geti(ParamMaxSeparableKernelSize)
+	if !k.valid() || len(k.X) > max || len(k.Y) > max {
+		return newError("failed to convolve", IllegalArgumentError)
+	}
+	s, err := newFilterSource("convolve", dst, src, tiling)
+	if err != nil {
+		return err
+	}
+	var kx, ky []tap
+	for c, w := range k.X {
+		kx = append(kx, tap{c - len(k.X)/2, float32(w)})
+	}
+	for r, w := range k.Y {
+		ky = append(ky, tap{len(k.Y)/2 - r, float32(w)})
+	}
+	scale := kernelScale(k.Scale)
+	out := s.separable(kx, ky)
+	s.write(dst, s.format, func(x, y int) [4]float32 {
+		v := out(x, y)
+		for i := range v {
+			v[i] = v[i]*scale + k.Bias
+		}
+		return v
+	})
+	return nil
+}
+
+// GaussianBlur wraps vgGaussianBlur. Pixels outside src are read according to
+// tiling.
+func (dst Image) GaussianBlur(src Image, stdDevX, stdDevY float32, tiling TilingMode) error {
+	max := Getf(ParamMaxGaussianStdDeviation)
+	if stdDevX <= 0 || stdDevY <= 0 || stdDevX > max || stdDevY > max {
+		return newError("failed to blur", IllegalArgumentError)
+	}
+	s, err := newFilterSource("blur", dst, src, tiling)
+	if err != nil {
+		return err
+	}
+	s.write(dst, s.format, s.separable(gaussian(stdDevX), gaussian(stdDevY)))
+	return nil
+}
+
+// Lookup wraps vgLookup, mapping each channel of src through its table. The
+// tables produce non-linear, non-premultiplied values.
+func (dst Image) Lookup(src Image, red, green, blue, alpha LookupTable) error {
+	s, err := newFilterSource("apply lookup", dst, src, TilePad)
+	if err != nil {
+		return err
+	}
+	tables := [4]*LookupTable{&red, &green, &blue, &alpha}
+	s.write(dst, filterFormat{}, func(x, y int) [4]float32 {
+		in := s.at(x, y)
+		var out [4]float32
+		for i, t := range tables {
+			out[i] = float32(t[lutIndex(in[i])]) / 0xff
+		}
+		return out
+	})
+	return nil
+}
+
+// LookupSingle wraps vgLookupSingle, mapping one channel of src to whole
+// non-premultiplied colors.
+func (dst Image) LookupSingle(src Image, channel ImageChannel, lut *[256]color.NRGBA) error {
+	index := map[ImageChannel]int{ChannelRed: 0, ChannelGreen: 1, ChannelBlue: 2, ChannelAlpha: 3}
+	ch, ok := index[channel]
+	if !ok {
+		return newError("failed to apply lookup", IllegalArgumentError)
+	}
+	s, err := newFilterSource("apply lookup", dst, src, TilePad)
+	if err != nil {
+		return err
+	}
+	s.write(dst, filterFormat{}, func(x, y int) [4]float32 {
+		c := lut[lutIndex(s.at(x, y)[ch])]
+		return [4]float32{float32(c.R) / 0xff, float32(c.G) / 0xff, float32(c.B) / 0xff, float32(c.A) / 0xff}
+	})
+	return nil
+}
+
+// filterFormat is the color space filters work in, selected by
+// ParamFilterFormatLinear and ParamFilterFormatPremultiplied.
+type filterFormat struct {
+	linear, premultiplied bool
+}
+
+// decode converts a stored premultiplied sRGB color into f.
+func (f filterFormat) decode(c color.RGBA) [4]float32 {
+	v := [4]float32{float32(c.R) / 0xff, float32(c.G) / 0xff, float32(c.B) / 0xff, float32(c.A) / 0xff}
+	return f.fromNonPremul(unpremul(v))
+}
+
+// fromNonPremul converts a non-premultiplied sRGB color into f.
+func (f filterFormat) fromNonPremul(v [4]float32) [4]float32 {
+	if f.linear {
+		for i := 0; i < 3; i++ {
+			v[i] = float32(sRGBToLinear(float64(v[i])))
+		}
+	}
+	if f.premultiplied {
+		for i := 0; i < 3; i++ {
+			v[i] *= v[3]
+		}
+	}
+	return v
+}
+
+// encode converts a color in f into the stored premultiplied sRGB form,
+// clamping it first.
+func (f filterFormat) encode(v [4]float32) color.RGBA {
+	v[3] = clampUnit(v[3])
+	for i := 0; i < 3; i++ {
+		v[i] = clampUnit(v[i])
+		if f.premultiplied {
+			v[i] = float32(math.Min(float64(v[i]), float64(v[3])))
+		}
+	}
+	if f.premultiplied {
+		v = unpremul(v)
+	}
+	if f.linear {
+		for i := 0; i < 3; i++ {
+			v[i] = float32(linearToSRGB(float64(v[i])))
+		}
+	}
+	return color.RGBA{
+		unitToByte(v[0] * v[3]),
+		unitToByte(v[1] * v[3]),
+		unitToByte(v[2] * v[3]),
+		unitToByte(v[3]),
+	}
+}
+
+func unpremul(v [4]float32) [4]float32 {
+	for i := 0; i < 3; i++ {
+		if v[3] > 0 {
+			v[i] = float32(math.Min(float64(v[i]/v[3]), 1))
+		} else {
+			v[i] = 0
+		}
+	}
+	return v
+}
+
+func lutIndex(v float32) int {
+	return int(clampUnit(v)*0xff + 0.5)
+}
+
+// filterSource holds the source of a filter converted to the filter format.
+// Coordinates are VG coordinates, with y up from the bottom row.
+type filterSource struct {
+	format        filterFormat
+	pix           [][4]float32
+	width, height int
+	// The area shared with the destination.
+	outWidth, outHeight int
+	tiling              TilingMode
+	fill                [4]float32
+}
+
+func newFilterSource(op string, dst, src Image, tiling TilingMode) (*filterSource, error) {
+	if dst.handle == nil || dst.handle.destroyed || src.handle == nil || src.handle.destroyed {
+		return nil, newError("failed to "+op+"", BadHandleError)
+	}
+	if dst.handle == src.handle {
+		return nil, newError("failed to "+op+"", IllegalArgumentError)
+	}
+	switch tiling {
+	case TileFill, TilePad, TileRepeat, TileReflect:
+	default:
+		return nil, newError("failed to "+op+"", IllegalArgumentError)
+	}
+	f := filterFormat{
+		linear:        Geti(ParamFilterFormatLinear) != 0,
+		premultiplied: Geti(ParamFilterFormatPremultiplied) != 0,
+	}
+	b := src.handle.pix.Bounds()
+	s := &filterSource{
+		format:    f,
+		pix:       make([][4]float32, b.Dx()*b.Dy()),
+		width:     b.Dx(),
+		height:    b.Dy(),
+		outWidth:  b.Dx(),
+		outHeight: b.Dy(),
+		tiling:    tiling,
+	}
+	if d := dst.handle.pix.Bounds(); d.Dx() < s.outWidth {
+		s.outWidth = d.Dx()
+	}
+	if d := dst.handle.pix.Bounds(); d.Dy() < s.outHeight {
+		s.outHeight = d.Dy()
+	}
+	for y := 0; y < s.height; y++ {
+		for x := 0; x < s.width; x++ {
+			s.pix[y*s.width+x] = f.decode(src.handle.pix.RGBAAt(b.Min.X+x, b.Max.Y-1-y))
+		}
+	}
+	fill := Getfv(ParamTileFillColor)
+	s.fill = f.fromNonPremul([4]float32{clampUnit(fill[0]), clampUnit(fill[1]), clampUnit(fill[2]), clampUnit(fill[3])})
+	return s, nil
+}
+
+// at returns the source pixel at (x, y), applying the tiling mode outside the
+// image.
+func (s *filterSource) at(x, y int) [4]float32 {
+	var ok bool
+	if x, ok = tileCoord(x, s.width, s.tiling); !ok {
+		return s.fill
+	}
+	if y, ok = tileCoord(y, s.height, s.tiling); !ok {
+		return s.fill
+	}
+	return s.pix[y*s.width+x]
+}
+
+func tileCoord(c, size int, tiling TilingMode) (int, bool) {
+	if c >= 0 && c < size {
+		return c, true
+	}
+	switch tiling {
+	case TilePad:
+		return clampInt(c, 0, size-1), true
+	case TileRepeat:
+		return (c%size + size) % size, true
+	case TileReflect:
+		c = (c%(2*size) + 2*size) % (2 * size)
+		if c >= size {
+			c = 2*size - 1 - c
+		}
+		return c, true
+	}
+	return 0, false
+}
+
+// write stores out(x, y), in format f, into the area of dst shared with the
+// source, leaving the channels outside ParamFilterChannelMask unchanged.
+func (s *filterSource) write(dst Image, f filterFormat, out func(x, y int) [4]float32) {
+	mask := ImageChannel(Geti(ParamFilterChannelMask))
+	channels := [4]ImageChannel{ChannelRed, ChannelGreen, ChannelBlue, ChannelAlpha}
+	pix := dst.handle.pix
+	b := pix.Bounds()
+	result := make([]color.RGBA, s.outWidth*s.outHeight)
+	for y := 0; y < s.outHeight; y++ {
+		for x := 0; x < s.outWidth; x++ {
+			v := out(x, y)
+			if mask&ChannelRGB != ChannelRGB || mask&ChannelAlpha == 0 {
+				old := f.decode(pix.RGBAAt(b.Min.X+x, b.Max.Y-1-y))
+				for i, ch := range channels {
+					if mask&ch == 0 {
+						v[i] = old[i]
+					}
+				}
+			}
+			result[y*s.outWidth+x] = dst.handle.format.normalize(f.encode(v))
+		}
+	}
+	for y := 0; y < s.outHeight; y++ {
+		for x := 0; x < s.outWidth; x++ {
+			pix.SetRGBA(b.Min.X+x, b.Max.Y-1-y, result[y*s.outWidth+x])
+		}
+	}
+}
+
+// tap is one weight of a one-dimensional kernel, at offset d from the output
+// pixel.
+type tap struct {
+	d int
+	w float32
+}
+
+// separable returns the convolution of the source with the horizontal
+// kernel kx and the vertical kernel ky, whose offsets are in VG coordinates.
+func (s *filterSource) separable(kx, ky []tap) func(x, y int) [4]float32 {
+	minDy, maxDy := 0, 0
+	for _, t := range ky {
+		if t.d < minDy {
+			minDy = t.d
+		}
+		if t.d > maxDy {
+			maxDy = t.d
+		}
+	}
+	// Convolve the rows the vertical pass reads with kx first.
+	w, rows := s.outWidth, s.outHeight+maxDy-minDy
+	tmp := make([][4]float32, w*rows)
+	for r := 0; r < rows; r++ {
+		for x := 0; x < w; x++ {
+			var sum [4]float32
+			for _, t := range kx {
+				in := s.at(x+t.d, r+minDy)
+				for i := range sum {
+					sum[i] += t.w * in[i]
+				}
+			}
+			tmp[r*w+x] = sum
+		}
+	}
+	return func(x, y int) [4]float32 {
+		var sum [4]float32
+		for _, t := range ky {
+			in := tmp[(y+t.d-minDy)*w+x]
+			for i := range sum {
+				sum[i] += t.w * in[i]
+			}
+		}
+		return sum
+	}
+}
+
+// gaussian returns a normalized Gaussian kernel.
+func gaussian(stdDev float32) []tap {
+	radius := int(math.Ceil(3 * float64(stdDev)))
+	taps := make([]tap, 0, 2*radius+1)
+	var total float32
+	for d := -radius; d <= radius; d++ {
+		w := float32(math.Exp(-float64(d*d) / (2 * float64(stdDev) * float64(stdDev))))
+		taps = append(taps, tap{d, w})
+		total += w
+	}
+	for i := range taps {
+		taps[i].w /= total
+	}
+	return taps
+}
+
+// sRGBToLinear inverts the sRGB transfer function for a value in [0, 1].
+func sRGBToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
@@ -0,0 +1,97 @@
+package openvg
+
+// ImageChannel represents a VGImageChannel. Channels may be combined into a
+// mask for ParamFilterChannelMask.
+type ImageChannel int
+
+const (
+	ChannelRed   = ImageChannel(1 << 3)
+	ChannelGreen = ImageChannel(1 << 2)
+	ChannelBlue  = ImageChannel(1 << 1)
+	ChannelAlpha = ImageChannel(1 << 0)
+
+	ChannelRGB = ChannelRed | ChannelGreen | ChannelBlue
+)
+
+// ColorMatrix transforms colors by rows: the output red is
+// m[0][0]*R + m[0][1]*G + m[0][2]*B + m[0][3]*A + m[0][4], and likewise for
+// green, blue and alpha. Channels are in [0, 1].
+type ColorMatrix [4][5]float32
+
+// IdentityColorMatrix leaves colors unchanged.
+var IdentityColorMatrix = ColorMatrix{
+	{1, 0, 0, 0, 0},
+	{0, 1, 0, 0, 0},
+	{0, 0, 1, 0, 0},
+	{0, 0, 0, 1, 0},
+}
+
+// vgMatrix returns m in the column-major layout of vgColorMatrix.
+func (m ColorMatrix) vgMatrix() [20]float32 {
+	var v [20]float32
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 5; col++ {
+			v[col*4+row] = m[row][col]
+		}
+	}
+	return v
+}
+
+// Kernel is a convolution kernel. Values holds Width*Height weights by rows,
+// top row first, and is centered on the output pixel at (Width/2, Height/2).
+// Each output is Scale times the weighted sum of its neighbourhood plus Bias; a
+// zero Scale is treated as 1.
+type Kernel struct {
+	Width, Height int
+	Values        []int16
+	Scale, Bias   float32
+}
+
+func (k Kernel) valid() bool {
+	return k.Width > 0 && k.Height > 0 && len(k.Values) == k.Width*k.Height
+}
+
+// vgKernel returns k in the column-major, bottom-up layout of vgConvolve,
+// which also flips the kernel, along with the matching shift.
+func (k Kernel) vgKernel() (values []int16, shiftX, shiftY int) {
+	w, h := k.Width, k.Height
+	values = make([]int16, w*h)
+	for i := 0; i < w; i++ {
+		for j := 0; j < h; j++ {
+			values[i*h+j] = k.Values[j*w+(w-1-i)]
+		}
+	}
+	return values, w / 2, h - 1 - h/2
+}
+
+// SeparableKernel is a convolution kernel that is the product of a horizontal
+// kernel X, left to right, and a vertical kernel Y, top to bottom. Both are
+// centered on the output pixel, and Scale and Bias apply as for Kernel.
+type SeparableKernel struct {
+	X, Y        []int16
+	Scale, Bias float32
+}
+
+func (k SeparableKernel) valid() bool {
+	return len(k.X) > 0 && len(k.Y) > 0
+}
+
+// vgKernels returns k in the layout of vgSeparableConvolve, which flips the
+// kernel, along with the matching shift.
+func (k SeparableKernel) vgKernels() (x, y []int16, shiftX, shiftY int) {
+	x = make([]int16, len(k.X))
+	for i, v := range k.X {
+		x[len(x)-1-i] = v
+	}
+	return x, k.Y, len(k.X) / 2, len(k.Y) - 1 - len(k.Y)/2
+}
+
+func kernelScale(scale float32) float32 {
+	if scale == 0 {
+		return 1
+	}
+	return scale
+}
+
+// LookupTable maps each 8-bit channel value to a new value.
+type LookupTable [256]uint8
@@ -0,0 +1,246 @@
+package openvg
+
+import "image/color"
+
+// ImageFormat represents a VGImageFormat. The 32-bit and 16-bit formats are
+// defined on native words and are named from the most significant bits, so
+// ImageFormatSrgbx8888 holds R in the high byte. The s and l prefixes select
+// the sRGB and linear color spaces.
+type ImageFormat int
+
+// Values from VGImageFormat.
+const (
+	ImageFormatSrgbx8888    = ImageFormat(0)
+	ImageFormatSrgba8888    = ImageFormat(1)
+	ImageFormatSrgba8888Pre = ImageFormat(2)
+	ImageFormatSrgb565      = ImageFormat(3)
+	ImageFormatSrgba5551    = ImageFormat(4)
+	ImageFormatSrgba4444    = ImageFormat(5)
+	ImageFormatSl8          = ImageFormat(6)
+	ImageFormatLrgbx8888    = ImageFormat(7)
+	ImageFormatLrgba8888    = ImageFormat(8)
+	ImageFormatLrgba8888Pre = ImageFormat(9)
+	ImageFormatLl8          = ImageFormat(10)
+	ImageFormatA8           = ImageFormat(11)
+	ImageFormatBw1          = ImageFormat(12)
+	ImageFormatA1           = ImageFormat(13)
+	ImageFormatA4           = ImageFormat(14)
+
+	ImageFormatSxrgb8888    = ImageFormat(0 | 1<<6)
+	ImageFormatSargb8888    = ImageFormat(1 | 1<<6)
+	ImageFormatSargb8888Pre = ImageFormat(2 | 1<<6)
+	ImageFormatSargb1555    = ImageFormat(4 | 1<<6)
+	ImageFormatSargb4444    = ImageFormat(5 | 1<<6)
+	ImageFormatLxrgb8888    = ImageFormat(7 | 1<<6)
+	ImageFormatLargb8888    = ImageFormat(8 | 1<<6)
+	ImageFormatLargb8888Pre = ImageFormat(9 | 1<<6)
+
+	ImageFormatSbgrx8888    = ImageFormat(0 | 1<<7)
+	ImageFormatSbgra8888    = ImageFormat(1 | 1<<7)
+	ImageFormatSbgra8888Pre = ImageFormat(2 | 1<<7)
+	ImageFormatSbgr565      = ImageFormat(3 | 1<<7)
+	ImageFormatSbgra5551    = ImageFormat(4 | 1<<7)
+	ImageFormatSbgra4444    = ImageFormat(5 | 1<<7)
+	ImageFormatLbgrx8888    = ImageFormat(7 | 1<<7)
+	ImageFormatLbgra8888    = ImageFormat(8 | 1<<7)
+	ImageFormatLbgra8888Pre = ImageFormat(9 | 1<<7)
+
+	ImageFormatSxbgr8888    = ImageFormat(0 | 1<<6 | 1<<7)
+	ImageFormatSabgr8888    = ImageFormat(1 | 1<<6 | 1<<7)
+	ImageFormatSabgr8888Pre = ImageFormat(2 | 1<<6 | 1<<7)
+	ImageFormatSabgr1555    = ImageFormat(4 | 1<<6 | 1<<7)
+	ImageFormatSabgr4444    = ImageFormat(5 | 1<<6 | 1<<7)
+	ImageFormatLxbgr8888    = ImageFormat(7 | 1<<6 | 1<<7)
+	ImageFormatLabgr8888    = ImageFormat(8 | 1<<6 | 1<<7)
+	ImageFormatLabgr8888Pre = ImageFormat(9 | 1<<6 | 1<<7)
+)
+
+// The channel order bits of a VGImageFormat, applied to a base RGBA format.
+const (
+	formatAlphaFirst = 1 << 6
+	formatBGR        = 1 << 7
+)
+
+var imageFormatNames = map[ImageFormat]string{
+	ImageFormatSrgbx8888:    "VG_sRGBX_8888",
+	ImageFormatSrgba8888:    "VG_sRGBA_8888",
+	ImageFormatSrgba8888Pre: "VG_sRGBA_8888_PRE",
+	ImageFormatSrgb565:      "VG_sRGB_565",
+	ImageFormatSrgba5551:    "VG_sRGBA_5551",
+	ImageFormatSrgba4444:    "VG_sRGBA_4444",
+	ImageFormatSl8:          "VG_sL_8",
+	ImageFormatLrgbx8888:    "VG_lRGBX_8888",
+	ImageFormatLrgba8888:    "VG_lRGBA_8888",
+	ImageFormatLrgba8888Pre: "VG_lRGBA_8888_PRE",
+	ImageFormatLl8:          "VG_lL_8",
+	ImageFormatA8:           "VG_A_8",
+	ImageFormatBw1:          "VG_BW_1",
+	ImageFormatA1:           "VG_A_1",
+	ImageFormatA4:           "VG_A_4",
+	ImageFormatSxrgb8888:    "VG_sXRGB_8888",
+	ImageFormatSargb8888:    "VG_sARGB_8888",
+	ImageFormatSargb8888Pre: "VG_sARGB_8888_PRE",
+	ImageFormatSargb1555:    "VG_sARGB_1555",
+	ImageFormatSargb4444:    "VG_sARGB_4444",
+	ImageFormatLxrgb8888:    "VG_lXRGB_8888",
+	ImageFormatLargb8888:    "VG_lARGB_8888",
+	ImageFormatLargb8888Pre: "VG_lARGB_8888_PRE",
+	ImageFormatSbgrx8888:    "VG_sBGRX_8888",
+	ImageFormatSbgra8888:    "VG_sBGRA_8888",
+	ImageFormatSbgra8888Pre: "VG_sBGRA_8888_PRE",
+	ImageFormatSbgr565:      "VG_sBGR_565",
+	ImageFormatSbgra5551:    "VG_sBGRA_5551",
+	ImageFormatSbgra4444:    "VG_sBGRA_4444",
+	ImageFormatLbgrx8888:    "VG_lBGRX_8888",
+	ImageFormatLbgra8888:    "VG_lBGRA_8888",
+	ImageFormatLbgra8888Pre: "VG_lBGRA_8888_PRE",
+	ImageFormatSxbgr8888:    "VG_sXBGR_8888",
+	ImageFormatSabgr8888:    "VG_sABGR_8888",
+	ImageFormatSabgr8888Pre: "VG_sABGR_8888_PRE",
+	ImageFormatSabgr1555:    "VG_sABGR_1555",
+	ImageFormatSabgr4444:    "VG_sABGR_4444",
+	ImageFormatLxbgr8888:    "VG_lXBGR_8888",
+	ImageFormatLabgr8888:    "VG_lABGR_8888",
+	ImageFormatLabgr8888Pre: "VG_lABGR_8888_PRE",
+}
+
+func (f ImageFormat) String() string {
+	if name, ok := imageFormatNames[f]; ok {
+		return name
+	}
+	return "VG_IMAGE_FORMAT_INVALID"
+}
+
+func (f ImageFormat) valid() bool {
+	_, ok := imageFormatNames[f]
+	return ok
+}
+
+// base returns f without its channel order bits.
+func (f ImageFormat) base() ImageFormat {
+	return f &^ (formatAlphaFirst | formatBGR)
+}
+
+// Premultiplied reports whether the color channels of f are premultiplied by
+// alpha.
+func (f ImageFormat) Premultiplied() bool {
+	switch f.base() {
+	case ImageFormatSrgba8888Pre, ImageFormatLrgba8888Pre:
+		return true
+	}
+	return false
+}
+
+// Linear reports whether f stores linear rather than sRGB color values.
+func (f ImageFormat) Linear() bool {
+	switch f.base() {
+	case ImageFormatLrgbx8888, ImageFormatLrgba8888, ImageFormatLrgba8888Pre, ImageFormatLl8:
+		return true
+	}
+	return false
+}
+
+// BitsPerPixel returns the size of one pixel of f, or 0 if f is not a valid
+// format.
+func (f ImageFormat) BitsPerPixel() int {
+	if !f.valid() {
+		return 0
+	}
+	switch f.base() {
+	case ImageFormatSrgb565, ImageFormatSrgba5551, ImageFormatSrgba4444:
+		return 16
+	case ImageFormatSl8, ImageFormatLl8, ImageFormatA8:
+		return 8
+	case ImageFormatA4:
+		return 4
+	case ImageFormatBw1, ImageFormatA1:
+		return 1
+	}
+	return 32
+}
+
+// ChannelSizes returns the number of bits of f in each color channel. The
+// luminance formats have only luminance and the alpha-only formats only alpha;
+// the X formats have no alpha. All sizes are 0 if f is not a valid format.
+func (f ImageFormat) ChannelSizes() (red, green, blue, alpha, luminance int) {
+	if !f.valid() {
+		return 0, 0, 0, 0, 0
+	}
+	switch f.base() {
+	case ImageFormatSrgbx8888, ImageFormatLrgbx8888:
+		return 8, 8, 8, 0, 0
+	case ImageFormatSrgb565:
+		return 5, 6, 5, 0, 0
+	case ImageFormatSrgba5551:
+		return 5, 5, 5, 1, 0
+	case ImageFormatSrgba4444:
+		return 4, 4, 4, 4, 0
+	case ImageFormatSl8, ImageFormatLl8:
+		return 0, 0, 0, 0, 8
+	case ImageFormatBw1:
+		return 0, 0, 0, 0, 1
+	case ImageFormatA8:
+		return 0, 0, 0, 8, 0
+	case ImageFormatA4:
+		return 0, 0, 0, 4, 0
+	case ImageFormatA1:
+		return 0, 0, 0, 1, 0
+	}
+	return 8, 8, 8, 8, 0
+}
+
+// ColorModel returns the Go color model closest to f. The image/color models
+// carry no color space, so linear formats map to the same models as their sRGB
+// counterparts; see Linear.
+func (f ImageFormat) ColorModel() color.Model {
+	switch f.base() {
+	case ImageFormatSl8, ImageFormatLl8:
+		return color.GrayModel
+	case ImageFormatA8, ImageFormatA1, ImageFormatA4:
+		return color.AlphaModel
+	case ImageFormatBw1:
+		return color.Palette{color.Black, color.White}
+	case ImageFormatSrgbx8888, ImageFormatLrgbx8888, ImageFormatSrgb565:
+		return color.RGBAModel
+	}
+	if f.Premultiplied() {
+		return color.RGBAModel
+	}
+	return color.NRGBAModel
+}
+
+// pixFmtNames maps formats to the ffmpeg pixel format with the same memory
+// layout on a little-endian host. ffmpeg names packed RGB formats by byte order,
+// so the VG name reads backwards.
+var pixFmtNames = map[ImageFormat]string{
+	ImageFormatSrgbx8888: "0bgr",
+	ImageFormatSrgba8888: "abgr",
+	ImageFormatSxrgb8888: "bgr0",
+	ImageFormatSargb8888: "bgra",
+	ImageFormatSbgrx8888: "0rgb",
+	ImageFormatSbgra8888: "argb",
+	ImageFormatSxbgr8888: "rgb0",
+	ImageFormatSabgr8888: "rgba",
+	ImageFormatSrgb565:   "rgb565le",
+	ImageFormatSbgr565:   "bgr565le",
+	ImageFormatSl8:       "gray",
+}
+
+// PixFmtName returns the name of the ffmpeg pixel format, as returned by
+// av_get_pix_fmt_name, with the same layout as f on a little-endian host. It
+// returns "" if ffmpeg has no equivalent, as for the linear and premultiplied
+// formats.
+func (f ImageFormat) PixFmtName() string {
+	return pixFmtNames[f]
+}
+
+// ImageFormatForPixFmt returns the format with the same layout as the named
+// ffmpeg pixel format on a little-endian host.
+func ImageFormatForPixFmt(name string) (ImageFormat, bool) {
+	for f, n := range pixFmtNames {
+		if n == name {
+			return f, true
+		}
+	}
+	return 0, false
+}
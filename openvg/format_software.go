@@ -0,0 +1,139 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"image/color"
+	"math"
+	"unsafe"
+)
+
+// Accelerated wraps vgHardwareQuery(VG_IMAGE_FORMAT_QUERY). Nothing is
+// hardware accelerated in the software backend.
+func (f ImageFormat) Accelerated() bool {
+	return false
+}
+
+// channelBits holds the widths of the R, G, B and A channels of the packed
+// base formats, whose channels are stored from the most significant bits in
+// that order.
+var channelBits = map[ImageFormat][4]uint{
+	ImageFormatSrgbx8888:    {8, 8, 8, 8},
+	ImageFormatSrgba8888:    {8, 8, 8, 8},
+	ImageFormatSrgba8888Pre: {8, 8, 8, 8},
+	ImageFormatSrgb565:      {5, 6, 5, 0},
+	ImageFormatSrgba5551:    {5, 5, 5, 1},
+	ImageFormatSrgba4444:    {4, 4, 4, 4},
+	ImageFormatLrgbx8888:    {8, 8, 8, 8},
+	ImageFormatLrgba8888:    {8, 8, 8, 8},
+	ImageFormatLrgba8888Pre: {8, 8, 8, 8},
+}
+
+// pixel decodes pixel col of a row of data in format f into a premultiplied
+// sRGB color, the storage format of software images.
+func (f ImageFormat) pixel(row unsafe.Pointer, col int) color.RGBA {
+	bits := f.BitsPerPixel()
+	var v uint32
+	switch bits {
+	case 32:
+		v = *(*uint32)(unsafe.Pointer(uintptr(row) + uintptr(col*4)))
+	case 16:
+		v = uint32(*(*uint16)(unsafe.Pointer(uintptr(row) + uintptr(col*2))))
+	case 8:
+		v = uint32(*(*uint8)(unsafe.Pointer(uintptr(row) + uintptr(col))))
+	default:
+		// Sub-byte formats store the leftmost pixel in the least significant
+		// bits.
+		b := *(*uint8)(unsafe.Pointer(uintptr(row) + uintptr(col*bits/8)))
+		v = uint32(b>>uint(col*bits%8)) & (1<<uint(bits) - 1)
+	}
+
+	var c [4]float64 // R, G, B, A in [0, 1]
+	switch f.base() {
+	case ImageFormatSl8, ImageFormatLl8:
+		l := float64(v) / 0xff
+		c = [4]float64{l, l, l, 1}
+	case ImageFormatBw1:
+		c = [4]float64{float64(v), float64(v), float64(v), 1}
+	case ImageFormatA8, ImageFormatA1, ImageFormatA4:
+		// Alpha-only images have white color channels.
+		c = [4]float64{1, 1, 1, float64(v) / float64(uint32(1)<<uint(bits)-1)}
+	default:
+		widths := channelBits[f.base()]
+		order := []int{0, 1, 2, 3}
+		switch f &^ f.base() {
+		case formatAlphaFirst:
+			order = []int{3, 0, 1, 2}
+		case formatBGR:
+			order = []int{2, 1, 0, 3}
+		case formatAlphaFirst | formatBGR:
+			order = []int{3, 2, 1, 0}
+		}
+		shift := uint(bits)
+		for _, ch := range order {
+			w := widths[ch]
+			if w == 0 {
+				c[ch] = 1
+				continue
+			}
+			shift -= w
+			mask := uint32(1)<<w - 1
+			c[ch] = float64(v>>shift&mask) / float64(mask)
+		}
+		switch f.base() {
+		case ImageFormatSrgbx8888, ImageFormatLrgbx8888:
+			c[3] = 1
+		}
+	}
+
+	if f.Premultiplied() {
+		for i := 0; i < 3; i++ {
+			if c[3] > 0 {
+				c[i] = math.Min(c[i]/c[3], 1)
+			} else {
+				c[i] = 0
+			}
+		}
+	}
+	if f.Linear() {
+		for i := 0; i < 3; i++ {
+			c[i] = linearToSRGB(c[i])
+		}
+	}
+	return color.RGBA{
+		uint8(c[0]*c[3]*0xff + 0.5),
+		uint8(c[1]*c[3]*0xff + 0.5),
+		uint8(c[2]*c[3]*0xff + 0.5),
+		uint8(c[3]*0xff + 0.5),
+	}
+}
+
+// linearToSRGB applies the sRGB transfer function to a linear value in [0, 1].
+func linearToSRGB(v float64) float64 {
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// normalize converts a premultiplied sRGB color into what an image of format f
+// can hold: formats without alpha are opaque, luminance formats are gray and
+// alpha-only formats are white.
+func (f ImageFormat) normalize(c color.RGBA) color.RGBA {
+	switch f.base() {
+	case ImageFormatSrgbx8888, ImageFormatLrgbx8888, ImageFormatSrgb565:
+		v := unpremul([4]float32{float32(c.R) / 0xff, float32(c.G) / 0xff, float32(c.B) / 0xff, float32(c.A) / 0xff})
+		return color.RGBA{unitToByte(v[0]), unitToByte(v[1]), unitToByte(v[2]), 0xff}
+	case ImageFormatSl8, ImageFormatLl8, ImageFormatBw1:
+		v := unpremul([4]float32{float32(c.R) / 0xff, float32(c.G) / 0xff, float32(c.B) / 0xff, float32(c.A) / 0xff})
+		l := unitToByte(0.2126*v[0] + 0.7152*v[1] + 0.0722*v[2])
+		if f.base() == ImageFormatBw1 {
+			l = 0xff * (l >> 7)
+		}
+		return color.RGBA{l, l, l, 0xff}
+	case ImageFormatA8, ImageFormatA1, ImageFormatA4:
+		return color.RGBA{c.A, c.A, c.A, c.A}
+	}
+	return c
+}
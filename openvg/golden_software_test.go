@@ -0,0 +1,266 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"flag"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden images in testdata")
+
+const goldenSize = 64
+
+// checkGolden compares the drawing surface with testdata/name.png, or rewrites
+// the file with -update. Channels may differ by a small tolerance, since
+// platforms that fuse multiply-adds can round coverage differently.
+func checkGolden(t *testing.T, name string) {
+	t.Helper()
+	got, err := ReadPixels(0, 0, goldenSize, goldenSize)
+	if err != nil {
+		t.Fatalf("ReadPixels: %v", err)
+	}
+	filename := filepath.Join("testdata", name+".png")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Create(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := png.Encode(f, got); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("%v; run with -update to create it", err)
+	}
+	defer f.Close()
+	want, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", filename, err)
+	}
+	if want.Bounds() != got.Bounds() {
+		t.Fatalf("%s is %v, drawn %v", filename, want.Bounds(), got.Bounds())
+	}
+	const tolerance = 2
+	bad := 0
+	for y := 0; y < goldenSize; y++ {
+		for x := 0; x < goldenSize; x++ {
+			r0, g0, b0, a0 := got.At(x, y).RGBA()
+			r1, g1, b1, a1 := want.At(x, y).RGBA()
+			for _, d := range [][2]uint32{{r0, r1}, {g0, g1}, {b0, b1}, {a0, a1}} {
+				if diff := int(d[0]>>8) - int(d[1]>>8); diff > tolerance || diff < -tolerance {
+					if bad == 0 {
+						t.Errorf("%s: first mismatch at (%d, %d): drew %v, want %v", name, x, y, got.At(x, y), want.At(x, y))
+					}
+					bad++
+					break
+				}
+			}
+		}
+	}
+	if bad > 0 {
+		t.Errorf("%s: %d pixels differ; run with -update if the change is intended", name, bad)
+	}
+}
+
+// bindGoldenSurface binds a white surface for a golden image test.
+func bindGoldenSurface(t *testing.T) *image.RGBA {
+	t.Helper()
+	surface := bindTestSurface(t, goldenSize, goldenSize)
+	SetClearColor(1, 1, 1, 1)
+	Clear(0, 0, goldenSize, goldenSize)
+	SetMatrixMode(MatrixPathUserToSurface)
+	LoadIdentity()
+	return surface
+}
+
+func newTestPath(t *testing.T, d *PathData) Path {
+	t.Helper()
+	p, err := CreatePath()
+	if err != nil {
+		t.Fatalf("CreatePath: %v", err)
+	}
+	t.Cleanup(func() { p.Destroy() })
+	if err := p.Append(d); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	return p
+}
+
+// setTestPaint creates a paint, calls setup on it and sets it for modes.
+func setTestPaint(t *testing.T, modes PaintMode, setup func(Paint) error) {
+	t.Helper()
+	p, err := CreatePaint()
+	if err != nil {
+		t.Fatalf("CreatePaint: %v", err)
+	}
+	t.Cleanup(func() { p.Destroy() })
+	if err := setup(p); err != nil {
+		t.Fatalf("setting up paint: %v", err)
+	}
+	if err := p.Set(modes); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+}
+
+func solid(r, g, b, a float32) func(Paint) error {
+	return func(p Paint) error { return p.SetColor(r, g, b, a) }
+}
+
+// star is a self-intersecting five-pointed star, whose center is a hole under
+// the even-odd rule.
+var star = new(PathData).Polygon([]float32{
+	32, 58, 47, 10, 7, 40, 57, 40, 17, 10,
+}, true)
+
+func TestGoldenPaths(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		draw func(t *testing.T)
+	}{
+		{"path_evenodd", func(t *testing.T) {
+			setTestPaint(t, FillPath, solid(0.8, 0, 0, 1))
+			Seti(ParamFillRule, int(FillRuleEvenOdd))
+			newTestPath(t, star).Draw(FillPath)
+		}},
+		{"path_nonzero", func(t *testing.T) {
+			setTestPaint(t, FillPath, solid(0.8, 0, 0, 1))
+			Seti(ParamFillRule, int(FillRuleNonZero))
+			newTestPath(t, star).Draw(FillPath)
+		}},
+		{"path_stroke", func(t *testing.T) {
+			setTestPaint(t, FillPath, solid(1, 0.8, 0, 1))
+			setTestPaint(t, StrokePath, solid(0, 0, 0.6, 1))
+			Setf(ParamStrokeLineWidth, 4)
+			Seti(ParamStrokeJoinStyle, int(JoinRound))
+			Seti(ParamStrokeCapStyle, int(CapRound))
+			newTestPath(t, new(PathData).RoundRect(8, 8, 48, 32, 16, 16)).Draw(FillPath | StrokePath)
+			newTestPath(t, new(PathData).Arc(12, 44, 40, 16, 0, 180, ArcOpen)).Draw(StrokePath)
+		}},
+		{"path_transform", func(t *testing.T) {
+			setTestPaint(t, FillPath, solid(0, 0.5, 0, 1))
+			Translate(32, 32)
+			Rotate(30)
+			Scale(1, 0.5)
+			newTestPath(t, new(PathData).Rect(-24, -24, 48, 48)).Draw(FillPath)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			bindGoldenSurface(t)
+			test.draw(t)
+			checkGolden(t, test.name)
+		})
+	}
+}
+
+func TestGoldenPaints(t *testing.T) {
+	stops := []GradientStop{
+		{Offset: 0, R: 1, G: 0, B: 0, A: 1},
+		{Offset: 0.5, R: 1, G: 1, B: 0, A: 1},
+		{Offset: 1, R: 0, G: 0, B: 1, A: 1},
+	}
+	square := new(PathData).Rect(4, 4, 56, 56)
+	for _, test := range []struct {
+		name string
+		draw func(t *testing.T)
+	}{
+		{"paint_linear", func(t *testing.T) {
+			setTestPaint(t, FillPath, func(p Paint) error {
+				return p.SetLinearGradient(16, 16, 48, 48, stops, SpreadReflect)
+			})
+			newTestPath(t, square).Draw(FillPath)
+		}},
+		{"paint_radial", func(t *testing.T) {
+			setTestPaint(t, FillPath, func(p Paint) error {
+				return p.SetRadialGradient(32, 32, 24, 40, 28, stops, SpreadPad)
+			})
+			newTestPath(t, new(PathData).Ellipse(32, 32, 56, 56)).Draw(FillPath)
+		}},
+		{"paint_translucent", func(t *testing.T) {
+			setTestPaint(t, FillPath, solid(1, 0, 0, 0.5))
+			newTestPath(t, new(PathData).Rect(4, 4, 36, 36)).Draw(FillPath)
+			setTestPaint(t, FillPath, solid(0, 0, 1, 0.5))
+			newTestPath(t, new(PathData).Rect(24, 24, 36, 36)).Draw(FillPath)
+		}},
+		{"paint_pattern", func(t *testing.T) {
+			img := writeTestImage(t)
+			setTestPaint(t, FillPath, func(p Paint) error {
+				return p.SetPattern(img, TileRepeat)
+			})
+			SetMatrixMode(MatrixFillPaintToUser)
+			Scale(4, 4)
+			newTestPath(t, new(PathData).Ellipse(32, 32, 56, 40)).Draw(FillPath)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			bindGoldenSurface(t)
+			test.draw(t)
+			checkGolden(t, test.name)
+		})
+	}
+}
+
+func TestGoldenMasks(t *testing.T) {
+	full := new(PathData).Rect(0, 0, goldenSize, goldenSize)
+	for _, test := range []struct {
+		name string
+		draw func(t *testing.T)
+	}{
+		{"mask_path", func(t *testing.T) {
+			circle := newTestPath(t, new(PathData).Ellipse(32, 32, 48, 48))
+			if err := circle.RenderToMask(FillPath, MaskSet); err != nil {
+				t.Fatalf("RenderToMask: %v", err)
+			}
+			hole := newTestPath(t, new(PathData).Ellipse(40, 40, 16, 16))
+			if err := hole.RenderToMask(FillPath, MaskSubtract); err != nil {
+				t.Fatalf("RenderToMask: %v", err)
+			}
+			SetMasking(true)
+			setTestPaint(t, FillPath, solid(0, 0.6, 0, 1))
+			newTestPath(t, full).Draw(FillPath)
+		}},
+		{"mask_layer", func(t *testing.T) {
+			layer, err := CreateMask(goldenSize, goldenSize)
+			if err != nil {
+				t.Fatalf("CreateMask: %v", err)
+			}
+			defer layer.Destroy()
+			layer.Fill(0, 0, goldenSize, goldenSize, 0)
+			layer.Fill(8, 8, 24, 48, 1)
+			layer.Fill(32, 8, 24, 48, 0.5)
+			if err := ModifyMask(layer, MaskSet, 0, 0, goldenSize, goldenSize); err != nil {
+				t.Fatalf("ModifyMask: %v", err)
+			}
+			// Clear the top half of the surface mask.
+			if err := ModifyMask(nil, MaskClear, 0, 32, goldenSize, 32); err != nil {
+				t.Fatalf("ModifyMask: %v", err)
+			}
+			SetMasking(true)
+			setTestPaint(t, FillPath, solid(0.5, 0, 0.5, 1))
+			newTestPath(t, full).Draw(FillPath)
+		}},
+		{"mask_scissor", func(t *testing.T) {
+			SetScissorRects([]PixelRect{{X: 4, Y: 4, Width: 24, Height: 24}, {X: 36, Y: 36, Width: 24, Height: 24}})
+			SetScissoring(true)
+			setTestPaint(t, FillPath, solid(0, 0, 0, 1))
+			newTestPath(t, new(PathData).Ellipse(32, 32, 56, 56)).Draw(FillPath)
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			bindGoldenSurface(t)
+			test.draw(t)
+			checkGolden(t, test.name)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !software
+// +build !software
+
+package openvg
+
+// #include "VG/openvg.h"
+import "C"
+
+// Child wraps vgChildImage, returning an image that shares the width x height
+// area of img at (x, y). img cannot be destroyed until its children are.
+func (img Image) Child(x, y, width, height int) (Image, error) {
+	handle := C.vgChildImage(img.handle, C.VGint(x), C.VGint(y), C.VGint(width), C.VGint(height))
+	if handle == C.VG_INVALID_HANDLE {
+		return Image{}, newError("failed to create child image", ErrorCode(C.vgGetError()))
+	}
+	child := Image{handle}
+	trackChild(img, child)
+	return child, nil
+}
+
+// Parent wraps vgGetParent. An image without a parent is its own parent.
+func (img Image) Parent() Image {
+	parent := Image{C.vgGetParent(img.handle)}
+	debugCheck("failed to get parent image")
+	return parent
+}
+
+// CopyFrom wraps vgCopyImage, copying the width x height area of src at
+// (sx, sy) to (dx, dy) in img. The areas may overlap.
+func (img Image) CopyFrom(dx, dy int, src Image, sx, sy, width, height int, dither bool) error {
+	cDither := C.VGboolean(C.VG_FALSE)
+	if dither {
+		cDither = C.VG_TRUE
+	}
+	C.vgCopyImage(
+		img.handle, C.VGint(dx), C.VGint(dy),
+		src.handle, C.VGint(sx), C.VGint(sy),
+		C.VGint(width), C.VGint(height), cDither)
+	return checkError("failed to copy image")
+}
+
+// Clear wraps vgClearImage, filling an area of img with ParamClearColor.
+func (img Image) Clear(x, y, width, height int) error {
+	C.vgClearImage(img.handle, C.VGint(x), C.VGint(y), C.VGint(width), C.VGint(height))
+	return checkError("failed to clear image")
+}
+
+// SetPixels wraps vgSetPixels, copying the width x height area of src at
+// (sx, sy) to (dx, dy) on the drawing surface, ignoring transforms and
+// blending.
+func SetPixels(dx, dy int, src Image, sx, sy, width, height int) error {
+	C.vgSetPixels(C.VGint(dx), C.VGint(dy), src.handle, C.VGint(sx), C.VGint(sy), C.VGint(width), C.VGint(height))
+	return checkError("failed to set pixels")
+}
+
+// GetPixels wraps vgGetPixels, copying the width x height area of the drawing
+// surface at (sx, sy) to (dx, dy) in dst.
+func GetPixels(dst Image, dx, dy, sx, sy, width, height int) error {
+	C.vgGetPixels(dst.handle, C.VGint(dx), C.VGint(dy), C.VGint(sx), C.VGint(sy), C.VGint(width), C.VGint(height))
+	return checkError("failed to get pixels")
+}
+
+// CopyPixels wraps vgCopyPixels, copying the width x height area of the
+// drawing surface at (sx, sy) to (dx, dy). The areas may overlap.
+func CopyPixels(dx, dy, sx, sy, width, height int) error {
+	C.vgCopyPixels(C.VGint(dx), C.VGint(dy), C.VGint(sx), C.VGint(sy), C.VGint(width), C.VGint(height))
+	return checkError("failed to copy pixels")
+}
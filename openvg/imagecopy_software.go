@@ -0,0 +1,164 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"image"
+	"image/color"
+)
+
+func (img Image) valid() bool {
+	return img.handle != nil && !img.handle.destroyed
+}
+
+// Child wraps vgChildImage, returning an image that shares the width x height
+// area of img at (x, y). img cannot be destroyed until its children are.
+func (img Image) Child(x, y, width, height int) (Image, error) {
+	if !img.valid() {
+		return Image{}, newError("failed to create child image", BadHandleError)
+	}
+	p := img.handle.pix
+	w, h := p.Bounds().Dx(), p.Bounds().Dy()
+	if x < 0 || y < 0 || width <= 0 || height <= 0 || x+width > w || y+height > h {
+		return Image{}, newError("failed to create child image", IllegalArgumentError)
+	}
+	// The child aliases the rows of the parent's pixels.
+	top := p.Bounds().Max.Y - (y + height)
+	pix := &image.RGBA{
+		Pix:    p.Pix[p.PixOffset(p.Bounds().Min.X+x, top):],
+		Stride: p.Stride,
+		Rect:   image.Rect(0, 0, width, height),
+	}
+	child := Image{&softImage{pix: pix, format: img.handle.format}}
+	trackChild(img, child)
+	return child, nil
+}
+
+// Parent wraps vgGetParent. An image without a parent is its own parent.
+func (img Image) Parent() Image {
+	if !img.valid() {
+		report("failed to get parent image", BadHandleError)
+	}
+	if parent, ok := parentOf(img); ok {
+		return parent
+	}
+	return img
+}
+
+// CopyFrom wraps vgCopyImage, copying the width x height area of src at
+// (sx, sy) to (dx, dy) in img. The areas may overlap.
+func (img Image) CopyFrom(dx, dy int, src Image, sx, sy, width, height int, dither bool) error {
+	if !img.valid() || !src.valid() {
+		return newError("failed to copy image", BadHandleError)
+	}
+	if width <= 0 || height <= 0 {
+		return newError("failed to copy image", IllegalArgumentError)
+	}
+	copyRect(img.handle.pix, dx, dy, src.handle.pix, sx, sy, width, height, img.handle.format.normalize)
+	return nil
+}
+
+// Clear wraps vgClearImage, filling an area of img with ParamClearColor.
+func (img Image) Clear(x, y, width, height int) error {
+	if !img.valid() {
+		return newError("failed to clear image", BadHandleError)
+	}
+	if width <= 0 || height <= 0 {
+		return newError("failed to clear image", IllegalArgumentError)
+	}
+	pix := img.handle.pix
+	r := vgRect(pix.Bounds(), x, y, width, height)
+	c := img.handle.format.normalize(paramColor(ParamClearColor))
+	for sy := r.Min.Y; sy < r.Max.Y; sy++ {
+		for sx := r.Min.X; sx < r.Max.X; sx++ {
+			pix.SetRGBA(sx, sy, c)
+		}
+	}
+	return nil
+}
+
+// SetPixels wraps vgSetPixels, copying the width x height area of src at
+// (sx, sy) to (dx, dy) on the drawing surface, ignoring transforms and
+// blending.
+func SetPixels(dx, dy int, src Image, sx, sy, width, height int) error {
+	if !src.valid() {
+		return newError("failed to set pixels", BadHandleError)
+	}
+	if width <= 0 || height <= 0 {
+		return newError("failed to set pixels", IllegalArgumentError)
+	}
+	if state.surface != nil {
+		copyRect(state.surface, dx, dy, src.handle.pix, sx, sy, width, height, nil)
+	}
+	return nil
+}
+
+// GetPixels wraps vgGetPixels, copying the width x height area of the drawing
+// surface at (sx, sy) to (dx, dy) in dst.
+func GetPixels(dst Image, dx, dy, sx, sy, width, height int) error {
+	if !dst.valid() {
+		return newError("failed to get pixels", BadHandleError)
+	}
+	if width <= 0 || height <= 0 {
+		return newError("failed to get pixels", IllegalArgumentError)
+	}
+	if state.surface != nil {
+		copyRect(dst.handle.pix, dx, dy, state.surface, sx, sy, width, height, dst.handle.format.normalize)
+	}
+	return nil
+}
+
+// CopyPixels wraps vgCopyPixels, copying the width x height area of the
+// drawing surface at (sx, sy) to (dx, dy). The areas may overlap.
+func CopyPixels(dx, dy, sx, sy, width, height int) error {
+	if width <= 0 || height <= 0 {
+		return newError("failed to copy pixels", IllegalArgumentError)
+	}
+	if state.surface != nil {
+		copyRect(state.surface, dx, dy, state.surface, sx, sy, width, height, nil)
+	}
+	return nil
+}
+
+// copyRect copies the width x height area of src at (sx, sy) to (dx, dy) in
+// dst, in VG coordinates, clipped to both images. dst and src may share
+// pixels. convert, if not nil, adapts each pixel to dst.
+func copyRect(dst *image.RGBA, dx, dy int, src *image.RGBA, sx, sy, width, height int, convert func(color.RGBA) color.RGBA) {
+	db, sb := dst.Bounds(), src.Bounds()
+	clip := func(d, s, n *int, dsize, ssize int) {
+		if *s < 0 {
+			*d, *n, *s = *d-*s, *n+*s, 0
+		}
+		if *d < 0 {
+			*s, *n, *d = *s-*d, *n+*d, 0
+		}
+		if *n > ssize-*s {
+			*n = ssize - *s
+		}
+		if *n > dsize-*d {
+			*n = dsize - *d
+		}
+	}
+	clip(&dx, &sx, &width, db.Dx(), sb.Dx())
+	clip(&dy, &sy, &height, db.Dy(), sb.Dy())
+	if width <= 0 || height <= 0 {
+		return
+	}
+	// Read everything first in case the areas overlap.
+	tmp := make([]color.RGBA, width*height)
+	for j := 0; j < height; j++ {
+		for i := 0; i < width; i++ {
+			c := src.RGBAAt(sb.Min.X+sx+i, sb.Max.Y-1-(sy+j))
+			if convert != nil {
+				c = convert(c)
+			}
+			tmp[j*width+i] = c
+		}
+	}
+	for j := 0; j < height; j++ {
+		for i := 0; i < width; i++ {
+			dst.SetRGBA(db.Min.X+dx+i, db.Max.Y-1-(dy+j), tmp[j*width+i])
+		}
+	}
+}
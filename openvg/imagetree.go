@@ -0,0 +1,59 @@
+package openvg
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrLiveChildren is returned when destroying an image whose child images are
+// still alive. VG would keep the shared pixels alive for the children, but a
+// parent destroyed first is almost always a lifecycle bug, so Destroy refuses.
+var ErrLiveChildren = errors.New("openvg: image has live child images")
+
+// imageParents maps each live child image to its parent, and imageChildren
+// counts the live children of each parent. imageTree guards both, since
+// contexts sharing images may create and destroy them on other threads.
+var (
+	imageTree     sync.Mutex
+	imageParents  = map[Image]Image{}
+	imageChildren = map[Image]int{}
+)
+
+func trackChild(parent, child Image) {
+	imageTree.Lock()
+	defer imageTree.Unlock()
+	imageParents[child] = parent
+	imageChildren[parent]++
+}
+
+// parentOf returns the parent of img, if img is a live child image.
+func parentOf(img Image) (Image, bool) {
+	imageTree.Lock()
+	defer imageTree.Unlock()
+	parent, ok := imageParents[img]
+	return parent, ok
+}
+
+// checkDestroy returns ErrLiveChildren if img has live children.
+func checkDestroy(img Image) error {
+	imageTree.Lock()
+	defer imageTree.Unlock()
+	if imageChildren[img] > 0 {
+		return ErrLiveChildren
+	}
+	return nil
+}
+
+// forgetImage stops tracking img after it is destroyed.
+func forgetImage(img Image) {
+	imageTree.Lock()
+	defer imageTree.Unlock()
+	parent, ok := imageParents[img]
+	if !ok {
+		return
+	}
+	delete(imageParents, img)
+	if imageChildren[parent]--; imageChildren[parent] == 0 {
+		delete(imageChildren, parent)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestImageTree(t *testing.T) {
+	parent, err := CreateImage(ImageFormatSrgba8888, 4, 4, nil)
+	if err != nil {
+		t.Fatalf("CreateImage: %v", err)
+	}
+	child, err := parent.Child(1, 1, 2, 2)
+	if err != nil {
+		t.Fatalf("Child: %v", err)
+	}
+	if got := child.Parent(); got != parent {
+		t.Errorf("child.Parent() = %v, want %v", got, parent)
+	}
+	if got := parent.Parent(); got != parent {
+		t.Errorf("parent.Parent() = %v, want the parent itself", got)
+	}
+	if err := parent.Destroy(); !errors.Is(err, ErrLiveChildren) {
+		t.Errorf("Destroy of a parent with a live child returned %v, want %v", err, ErrLiveChildren)
+	}
+	if err := child.Destroy(); err != nil {
+		t.Fatalf("Destroy(child): %v", err)
+	}
+	if err := parent.Destroy(); err != nil {
+		t.Errorf("Destroy(parent) after its child: %v", err)
+	}
+}
+
+func TestImageTreeConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				parent, err := CreateImage(ImageFormatSrgba8888, 2, 2, nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				child, err := parent.Child(0, 0, 1, 1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if child.Parent() != parent {
+					t.Error("child lost its parent")
+				}
+				if err := child.Destroy(); err != nil {
+					t.Error(err)
+				}
+				if err := parent.Destroy(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
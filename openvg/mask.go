@@ -0,0 +1,74 @@
+//go:build !software
+// +build !software
+
+package openvg
+
+// #include "VG/openvg.h"
+import "C"
+
+// Mask wraps a VGMaskLayer, a mask that can be saved from and combined into
+// the drawing surface mask.
+type Mask struct {
+	handle C.VGMaskLayer
+}
+
+// MaskSource is an Image or Mask that ModifyMask can combine into the drawing
+// surface mask. Images contribute their alpha.
+type MaskSource interface {
+	maskHandle() C.VGHandle
+}
+
+func (img Image) maskHandle() C.VGHandle {
+	return C.VGHandle(img.handle)
+}
+
+func (m Mask) maskHandle() C.VGHandle {
+	return C.VGHandle(m.handle)
+}
+
+// CreateMask wraps vgCreateMaskLayer. The mask starts filled with 1.
+func CreateMask(width, height int) (Mask, error) {
+	handle := C.vgCreateMaskLayer(C.VGint(width), C.VGint(height))
+	if handle == C.VG_INVALID_HANDLE {
+		return Mask{}, newError("failed to create mask", ErrorCode(C.vgGetError()))
+	}
+	return Mask{handle}, nil
+}
+
+// Destroy wraps vgDestroyMaskLayer.
+func (m Mask) Destroy() error {
+	C.vgDestroyMaskLayer(m.handle)
+	return checkError("failed to destroy mask")
+}
+
+// Fill wraps vgFillMaskLayer, setting an area of m to value in [0, 1].
+func (m Mask) Fill(x, y, width, height int, value float32) error {
+	C.vgFillMaskLayer(m.handle, C.VGint(x), C.VGint(y), C.VGint(width), C.VGint(height), C.VGfloat(value))
+	return checkError("failed to fill mask")
+}
+
+// CopyFrom wraps vgCopyMask, copying the width x height area of the drawing
+// surface mask at (sx, sy) to (dx, dy) in m.
+func (m Mask) CopyFrom(dx, dy, sx, sy, width, height int) error {
+	C.vgCopyMask(m.handle, C.VGint(dx), C.VGint(dy), C.VGint(sx), C.VGint(sy), C.VGint(width), C.VGint(height))
+	return checkError("failed to copy mask")
+}
+
+// ModifyMask wraps vgMask, combining src into the width x height area of the
+// drawing surface mask at (x, y) with op. src may be nil for MaskClear and
+// MaskFill.
+func ModifyMask(src MaskSource, op MaskOperation, x, y, width, height int) error {
+	handle := C.VGHandle(C.VG_INVALID_HANDLE)
+	if src != nil {
+		handle = src.maskHandle()
+	}
+	C.vgMask(handle, C.VGMaskOperation(op), C.VGint(x), C.VGint(y), C.VGint(width), C.VGint(height))
+	return checkError("failed to modify mask")
+}
+
+// RenderToMask wraps vgRenderToMask, combining the coverage of the path,
+// filled and/or stroked, into the drawing surface mask with op.
+func (p Path) RenderToMask(modes PaintMode, op MaskOperation) error {
+	C.vgRenderToMask(p.handle, C.VGbitfield(modes), C.VGMaskOperation(op))
+	return checkError("failed to render to mask")
+}
@@ -0,0 +1,274 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"image"
+)
+
+// Mask wraps a VGMaskLayer, a mask that can be saved from and combined into
+// the drawing surface mask.
+type Mask struct {
+	handle *softMask
+}
+
+type softMask struct {
+	width, height int
+	// values holds the mask by bottom-up rows, as VG addresses it.
+	values    []float32
+	destroyed bool
+}
+
+// MaskSource is an Image or Mask that ModifyMask can combine into the drawing
+// surface mask. Images contribute their alpha.
+type MaskSource interface {
+	// maskValue returns the value at (x, y) in VG coordinates, or false
+	// outside the source.
+	maskValue(x, y int) (float32, bool)
+	valid() bool
+}
+
+func (img Image) maskValue(x, y int) (float32, bool) {
+	pix := img.handle.pix
+	b := pix.Bounds()
+	if x < 0 || y < 0 || x >= b.Dx() || y >= b.Dy() {
+		return 0, false
+	}
+	c := pix.RGBAAt(b.Min.X+x, b.Max.Y-1-y)
+	switch img.handle.format.base() {
+	case ImageFormatSl8, ImageFormatLl8, ImageFormatBw1:
+		// Luminance images are opaque, so use their gray level instead.
+		return float32(c.R) / 0xff, true
+	}
+	return float32(c.A) / 0xff, true
+}
+
+func (m Mask) valid() bool {
+	return m.handle != nil && !m.handle.destroyed
+}
+
+func (m Mask) maskValue(x, y int) (float32, bool) {
+	if x < 0 || y < 0 || x >= m.handle.width || y >= m.handle.height {
+		return 0, false
+	}
+	return m.handle.values[y*m.handle.width+x], true
+}
+
+// CreateMask wraps vgCreateMaskLayer. The mask starts filled with 1.
+func CreateMask(width, height int) (Mask, error) {
+	if width <= 0 || height <= 0 {
+		return Mask{}, newError("failed to create mask", IllegalArgumentError)
+	}
+	values := make([]float32, width*height)
+	for i := range values {
+		values[i] = 1
+	}
+	return Mask{&softMask{width: width, height: height, values: values}}, nil
+}
+
+// Destroy wraps vgDestroyMaskLayer.
+func (m Mask) Destroy() error {
+	if !m.valid() {
+		return newError("failed to destroy mask", BadHandleError)
+	}
+	m.handle.destroyed = true
+	m.handle.values = nil
+	return nil
+}
+
+// Fill wraps vgFillMaskLayer, setting an area of m to value in [0, 1].
+func (m Mask) Fill(x, y, width, height int, value float32) error {
+	if !m.valid() {
+		return newError("failed to fill mask", BadHandleError)
+	}
+	if width <= 0 || height <= 0 || x < 0 || y < 0 || x+width > m.handle.width || y+height > m.handle.height ||
+		value < 0 || value > 1 {
+		return newError("failed to fill mask", IllegalArgumentError)
+	}
+	for j := y; j < y+height; j++ {
+		for i := x; i < x+width; i++ {
+			m.handle.values[j*m.handle.width+i] = value
+		}
+	}
+	return nil
+}
+
+// CopyFrom wraps vgCopyMask, copying the width x height area of the drawing
+// surface mask at (sx, sy) to (dx, dy) in m.
+func (m Mask) CopyFrom(dx, dy, sx, sy, width, height int) error {
+	if !m.valid() {
+		return newError("failed to copy mask", BadHandleError)
+	}
+	if width <= 0 || height <= 0 {
+		return newError("failed to copy mask", IllegalArgumentError)
+	}
+	if state.surface == nil {
+		return nil
+	}
+	sh := state.surface.Bounds().Dy()
+	sw := state.surface.Bounds().Dx()
+	for j := 0; j < height; j++ {
+		for i := 0; i < width; i++ {
+			x, y := sx+i, sy+j
+			tx, ty := dx+i, dy+j
+			if x < 0 || y < 0 || x >= sw || y >= sh || tx < 0 || ty < 0 || tx >= m.handle.width || ty >= m.handle.height {
+				continue
+			}
+			m.handle.values[ty*m.handle.width+tx] = surfaceMask(x, sh-1-y)
+		}
+	}
+	return nil
+}
+
+// ModifyMask wraps vgMask, combining src into the width x height area of the
+// drawing surface mask at (x, y) with op. src may be nil for MaskClear and
+// MaskFill.
+func ModifyMask(src MaskSource, op MaskOperation, x, y, width, height int) error {
+	if !op.valid() {
+		return newError("failed to modify mask", IllegalArgumentError)
+	}
+	if op != MaskClear && op != MaskFill && (src == nil || !src.valid()) {
+		return newError("failed to modify mask", BadHandleError)
+	}
+	if width <= 0 || height <= 0 {
+		return newError("failed to modify mask", IllegalArgumentError)
+	}
+	if state.surface == nil {
+		return nil
+	}
+	h := state.surface.Bounds().Dy()
+	applyMask(op, vgRect(state.surface.Bounds(), x, y, width, height), func(sx, sy int) (float32, bool) {
+		if src == nil {
+			return 0, true
+		}
+		return src.maskValue(sx-x, h-1-sy-y)
+	})
+	return nil
+}
+
+// RenderToMask wraps vgRenderToMask, combining the coverage of the path,
+// filled and/or stroked, into the drawing surface mask with op.
+func (p Path) RenderToMask(modes PaintMode, op MaskOperation) error {
+	if !p.valid() {
+		return newError("failed to render to mask", BadHandleError)
+	}
+	if !op.valid() || modes&^(FillPath|StrokePath) != 0 || modes == 0 {
+		return newError("failed to render to mask", IllegalArgumentError)
+	}
+	if state.surface == nil {
+		return nil
+	}
+	w, h := state.surface.Bounds().Dx(), state.surface.Bounds().Dy()
+	coverage := make([]float32, w*h)
+	plot := func(x, y int, c float32) {
+		// Fill and stroke cover the union of their areas.
+		i := y*w + x
+		coverage[i] = 1 - (1-coverage[i])*(1-c)
+	}
+	m := matrices[MatrixPathUserToSurface]
+	lines := p.handle.data.flatten(m)
+	if modes&FillPath != 0 {
+		var polys [][]pt
+		for _, line := range lines {
+			polys = append(polys, line.pts)
+		}
+		coverSurface(polys, m, FillRule(Geti(ParamFillRule)), plot)
+	}
+	if modes&StrokePath != 0 {
+		coverSurface(strokeOutline(lines), m, FillRuleNonZero, plot)
+	}
+	applyMask(op, state.surface.Bounds(), func(x, y int) (float32, bool) {
+		return coverage[y*w+x], true
+	})
+	return nil
+}
+
+// applyMask combines the values of src with the drawing surface mask within
+// r, in top-down surface coordinates. Pixels for which src returns false are
+// left unchanged.
+func applyMask(op MaskOperation, r image.Rectangle, src func(x, y int) (float32, bool)) {
+	w, h := state.surface.Bounds().Dx(), state.surface.Bounds().Dy()
+	if state.mask == nil {
+		state.mask = make([]float32, w*h)
+		for i := range state.mask {
+			state.mask[i] = 1
+		}
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			s, ok := src(x, y)
+			if !ok {
+				continue
+			}
+			m := &state.mask[y*w+x]
+			switch op {
+			case MaskClear:
+				*m = 0
+			case MaskFill:
+				*m = 1
+			case MaskSet:
+				*m = s
+			case MaskUnion:
+				*m = 1 - (1-s)*(1-*m)
+			case MaskIntersect:
+				*m *= s
+			case MaskSubtract:
+				*m *= 1 - s
+			}
+		}
+	}
+}
+
+// surfaceMask returns the drawing surface mask at (x, y) in top-down surface
+// coordinates.
+func surfaceMask(x, y int) float32 {
+	if state.mask == nil {
+		return 1
+	}
+	return state.mask[y*state.surface.Bounds().Dx()+x]
+}
+
+// updateClip caches the scissoring and masking parameters for blendPixel.
+func updateClip() {
+	state.masking = Geti(ParamMasking) != False
+	state.scissoring = Geti(ParamScissoring) != False
+	state.scissor = state.scissor[:0]
+	if state.surface == nil {
+		return
+	}
+	b := state.surface.Bounds()
+	rects := ScissorRects()
+	if max := Geti(ParamMaxScissorRects); len(rects) > max {
+		rects = rects[:max]
+	}
+	for _, r := range rects {
+		if r.Width > 0 && r.Height > 0 {
+			state.scissor = append(state.scissor, vgRect(b, r.X, r.Y, r.Width, r.Height))
+		}
+	}
+}
+
+// inScissor reports whether (x, y), in top-down surface coordinates, is inside
+// a scissor rectangle.
+func inScissor(x, y int) bool {
+	p := image.Pt(x, y)
+	for _, r := range state.scissor {
+		if p.In(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// clipCoverage returns how much of the pixel at (x, y), in top-down surface
+// coordinates, scissoring and masking let through.
+func clipCoverage(x, y int) float32 {
+	if state.scissoring && !inScissor(x, y) {
+		return 0
+	}
+	if state.masking {
+		return surfaceMask(x, y)
+	}
+	return 1
+}
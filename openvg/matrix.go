@@ -0,0 +1,151 @@
+package openvg
+
+import "math"
+
+// Matrix is a 3x3 VG transformation matrix in the column-major order used by
+// vgLoadMatrix: {sx, shy, w0, shx, sy, w1, tx, ty, w2}.
+type Matrix [9]float32
+
+// Identity is the identity transformation.
+var Identity = Matrix{1, 0, 0, 0, 1, 0, 0, 0, 1}
+
+// at returns the element in the given row and column.
+func (m Matrix) at(row, col int) float32 {
+	return m[col*3+row]
+}
+
+// Mul returns m * n, i.e. the transformation that applies n first.
+func (m Matrix) Mul(n Matrix) Matrix {
+	var r Matrix
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			var v float32
+			for k := 0; k < 3; k++ {
+				v += m.at(row, k) * n.at(k, col)
+			}
+			r[col*3+row] = v
+		}
+	}
+	return r
+}
+
+// Translated returns m * translate(tx, ty), matching vgTranslate.
+func (m Matrix) Translated(tx, ty float32) Matrix {
+	return m.Mul(Matrix{1, 0, 0, 0, 1, 0, tx, ty, 1})
+}
+
+// Scaled returns m * scale(sx, sy), matching vgScale.
+func (m Matrix) Scaled(sx, sy float32) Matrix {
+	return m.Mul(Matrix{sx, 0, 0, 0, sy, 0, 0, 0, 1})
+}
+
+// Sheared returns m * shear(shx, shy), matching vgShear.
+func (m Matrix) Sheared(shx, shy float32) Matrix {
+	return m.Mul(Matrix{1, shy, 0, shx, 1, 0, 0, 0, 1})
+}
+
+// Rotated returns m * rotate(angle), matching vgRotate. The angle is in
+// degrees, counterclockwise.
+func (m Matrix) Rotated(angle float32) Matrix {
+	sin, cos := math.Sincos(float64(angle) * math.Pi / 180)
+	s, c := float32(sin), float32(cos)
+	return m.Mul(Matrix{c, s, 0, -s, c, 0, 0, 0, 1})
+}
+
+// Apply transforms the point (x, y), including the projective divide.
+func (m Matrix) Apply(x, y float32) (float32, float32) {
+	w := m[2]*x + m[5]*y + m[8]
+	if w == 0 {
+		w = 1
+	}
+	return (m[0]*x + m[3]*y + m[6]) / w, (m[1]*x + m[4]*y + m[7]) / w
+}
+
+// Invert returns the inverse of m and whether m is invertible.
+func (m Matrix) Invert() (Matrix, bool) {
+	a, b, c := m.at(0, 0), m.at(0, 1), m.at(0, 2)
+	d, e, f := m.at(1, 0), m.at(1, 1), m.at(1, 2)
+	g, h, i := m.at(2, 0), m.at(2, 1), m.at(2, 2)
+	det := a*(e*i-f*h) - b*(d*i-f*g) + c*(d*h-e*g)
+	if det == 0 {
+		return Matrix{}, false
+	}
+	inv := 1 / det
+	rows := [3][3]float32{
+		{(e*i - f*h) * inv, (c*h - b*i) * inv, (b*f - c*e) * inv},
+		{(f*g - d*i) * inv, (a*i - c*g) * inv, (c*d - a*f) * inv},
+		{(d*h - e*g) * inv, (b*g - a*h) * inv, (a*e - b*d) * inv},
+	}
+	var r Matrix
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			r[col*3+row] = rows[row][col]
+		}
+	}
+	return r, true
+}
+
+// Rect is a rectangle in surface coordinates, whose origin is the bottom-left
+// corner of the surface.
+type Rect struct {
+	X, Y, Width, Height float32
+}
+
+// ScaleMode selects how an image is placed into a destination Rect.
+type ScaleMode int
+
+const (
+	// ScaleFit stretches the image to exactly cover the destination, ignoring
+	// its aspect ratio.
+	ScaleFit ScaleMode = iota
+	// ScaleFill preserves the aspect ratio and covers the destination. The
+	// overflow on one axis is drawn outside the destination unless clipped,
+	// e.g. by scissoring.
+	ScaleFill
+	// ScaleLetterbox preserves the aspect ratio and fits the whole image
+	// inside the destination, centered.
+	ScaleLetterbox
+)
+
+// PlacementMatrix returns the image-user-to-surface matrix that draws an
+// image of the given size into dst. If mirror is set the image is flipped
+// horizontally, as in a selfie view.
+func PlacementMatrix(width, height int, dst Rect, mode ScaleMode, mirror bool) Matrix {
+	if width <= 0 || height <= 0 {
+		return Identity
+	}
+	sx := dst.Width / float32(width)
+	sy := dst.Height / float32(height)
+	switch mode {
+	case ScaleFill:
+		sx = float32(math.Max(float64(sx), float64(sy)))
+		sy = sx
+	case ScaleLetterbox:
+		sx = float32(math.Min(float64(sx), float64(sy)))
+		sy = sx
+	}
+	w, h := sx*float32(width), sy*float32(height)
+	m := Identity.Translated(dst.X+(dst.Width-w)/2, dst.Y+(dst.Height-h)/2)
+	if mirror {
+		m = m.Translated(w, 0).Scaled(-1, 1)
+	}
+	return m.Scaled(sx, sy)
+}
+
+// SetMatrixMode selects the matrix manipulated by the transform functions.
+func SetMatrixMode(mode MatrixMode) error {
+	return Seti(ParamMatrixMode, int(mode))
+}
+
+// DrawIn draws img into dst with the given scale mode, replacing the
+// image-user-to-surface matrix. The matrix mode is left at
+// MatrixImageUserToSurface.
+func (img Image) DrawIn(dst Rect, mode ScaleMode, mirror bool) error {
+	if err := SetMatrixMode(MatrixImageUserToSurface); err != nil {
+		return err
+	}
+	if err := LoadMatrix(PlacementMatrix(img.Width(), img.Height(), dst, mode, mirror)); err != nil {
+		return err
+	}
+	return img.Draw()
+}
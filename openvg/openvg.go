@@ -1,3 +1,6 @@
+//go:build !software
+// +build !software
+
 package openvg
 
 // #cgo CFLAGS: -I/opt/vc/include
@@ -6,23 +9,84 @@ package openvg
 import "C"
 import (
 	"fmt"
+	"image"
 	"unsafe"
 )
 
-func SetClearColor() {
-	clearColor := []C.float{1, 1, 1, 1}
-	C.vgSetfv(C.VG_CLEAR_COLOR, 4, &clearColor[0])
+// SetClearColor sets the color used by Clear, with components in [0, 1].
+func SetClearColor(r, g, b, a float32) error {
+	return Setfv(ParamClearColor, []float32{r, g, b, a})
 }
 
-func Clear(x, y, w, h int) {
-	C.vgClear(C.VGint(x), C.VGint(y), C.VGint(w), C.VGint(h))
+// Setf wraps vgSetf.
+func Setf(param ParamType, value float32) error {
+	C.vgSetf(C.VGParamType(param), C.VGfloat(value))
+	return checkParam("set", param)
 }
 
-type ImageFormat C.VGImageFormat
+// Seti wraps vgSeti.
+func Seti(param ParamType, value int) error {
+	C.vgSeti(C.VGParamType(param), C.VGint(value))
+	return checkParam("set", param)
+}
 
-const (
-	ImageFormatSrgbx8888 = ImageFormat(C.VG_sRGBX_8888)
-)
+// Setfv wraps vgSetfv.
+func Setfv(param ParamType, values []float32) error {
+	var p *C.VGfloat
+	if len(values) > 0 {
+		p = (*C.VGfloat)(unsafe.Pointer(&values[0]))
+	}
+	C.vgSetfv(C.VGParamType(param), C.VGint(len(values)), p)
+	return checkParam("set", param)
+}
+
+// Getf wraps vgGetf.
+func Getf(param ParamType) float32 {
+	v := float32(C.vgGetf(C.VGParamType(param)))
+	debugCheck("failed to get parameter")
+	return v
+}
+
+// Geti wraps vgGeti.
+func Geti(param ParamType) int {
+	v := int(C.vgGeti(C.VGParamType(param)))
+	debugCheck("failed to get parameter")
+	return v
+}
+
+// Getfv wraps vgGetVectorSize and vgGetfv.
+func Getfv(param ParamType) []float32 {
+	n := int(C.vgGetVectorSize(C.VGParamType(param)))
+	if n <= 0 {
+		debugCheck("failed to get parameter")
+		return nil
+	}
+	values := make([]float32, n)
+	C.vgGetfv(C.VGParamType(param), C.VGint(n), (*C.VGfloat)(unsafe.Pointer(&values[0])))
+	debugCheck("failed to get parameter")
+	return values
+}
+
+func checkParam(op string, param ParamType) error {
+	if err := C.vgGetError(); err != C.VG_NO_ERROR {
+		return newError(fmt.Sprintf("failed to %s parameter %#x", op, int(param)), ErrorCode(err))
+	}
+	return nil
+}
+
+// Clear wraps vgClear.
+func Clear(x, y, w, h int) error {
+	C.vgClear(C.VGint(x), C.VGint(y), C.VGint(w), C.VGint(h))
+	return checkError("failed to clear")
+}
+
+// Accelerated wraps vgHardwareQuery(VG_IMAGE_FORMAT_QUERY), reporting whether
+// images of format f are hardware accelerated.
+func (f ImageFormat) Accelerated() bool {
+	accelerated := C.vgHardwareQuery(C.VG_IMAGE_FORMAT_QUERY, C.VGint(f)) == C.VG_HARDWARE_ACCELERATED
+	debugCheck("failed to query hardware")
+	return accelerated
+}
 
 type ImageQuality C.VGImageQuality
 
@@ -43,23 +107,58 @@ func CreateImage(format ImageFormat, width, height int, quality []ImageQuality)
 	}
 	handle := C.vgCreateImage(C.VGImageFormat(format), C.VGint(width), C.VGint(height), qualityBitField)
 	if handle == C.VG_INVALID_HANDLE {
-		return Image{}, fmt.Errorf("openvg: failed to create image: %s", errNames[C.vgGetError()])
+		return Image{}, newError("failed to create image", ErrorCode(C.vgGetError()))
 	}
 	return Image{handle}, nil
 }
 
-// Destroy wraps vgDestroyImage.
+// Width wraps vgGetParameteri(VG_IMAGE_WIDTH).
+func (img Image) Width() int {
+	return int(img.parameteri(C.VG_IMAGE_WIDTH))
+}
+
+// Height wraps vgGetParameteri(VG_IMAGE_HEIGHT).
+func (img Image) Height() int {
+	return int(img.parameteri(C.VG_IMAGE_HEIGHT))
+}
+
+// Format wraps vgGetParameteri(VG_IMAGE_FORMAT).
+func (img Image) Format() ImageFormat {
+	return ImageFormat(img.parameteri(C.VG_IMAGE_FORMAT))
+}
+
+func (img Image) parameteri(param C.VGint) C.VGint {
+	v := C.vgGetParameteri(C.VGHandle(img.handle), param)
+	debugCheck("failed to get image parameter")
+	return v
+}
+
+// ClientBufferHandle is the VGImage handle of an image, which egl passes to
+// eglCreatePbufferFromClientBuffer.
+type ClientBufferHandle uintptr
+
+// ClientBuffer returns the client buffer handle of img, through which egl
+// creates a pbuffer surface that renders into img.
+func (img Image) ClientBuffer() ClientBufferHandle {
+	return ClientBufferHandle(img.handle)
+}
+
+// Destroy wraps vgDestroyImage. It returns ErrLiveChildren, leaving img
+// alive, if img has child images that have not been destroyed.
 func (img Image) Destroy() error {
+	if err := checkDestroy(img); err != nil {
+		return err
+	}
 	C.vgDestroyImage(img.handle)
-	err := C.vgGetError()
-	if err != C.VG_NO_ERROR {
-		return fmt.Errorf("openvg: failed to destroy image: %s", errNames[err])
+	if err := checkError("failed to destroy image"); err != nil {
+		return err
 	}
+	forgetImage(img)
 	return nil
 }
 
 // Write calls vgImageSubData.
-func (img Image) Write(p unsafe.Pointer, stride int, fmt ImageFormat, x, y, width, height int) {
+func (img Image) Write(p unsafe.Pointer, stride int, fmt ImageFormat, x, y, width, height int) error {
 	C.vgImageSubData(
 		img.handle,
 		p,
@@ -70,16 +169,92 @@ func (img Image) Write(p unsafe.Pointer, stride int, fmt ImageFormat, x, y, widt
 		C.VGint(width),
 		C.VGint(height),
 	)
+	return checkError("failed to write image")
+}
+
+// Read wraps vgGetImageSubData, returning the width x height area of img at
+// (x, y) with the top row first.
+func (img Image) Read(x, y, width, height int) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, newError("failed to read image", IllegalArgumentError)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	// VG_sABGR_8888_PRE holds R in the least significant byte, which on the
+	// little-endian Pi is the R, G, B, A byte order of image.RGBA.
+	C.vgGetImageSubData(
+		img.handle,
+		unsafe.Pointer(&dst.Pix[0]),
+		C.VGint(dst.Stride),
+		C.VGImageFormat(ImageFormatSabgr8888Pre),
+		C.VGint(x),
+		C.VGint(y),
+		C.VGint(width),
+		C.VGint(height),
+	)
+	if err := checkError("failed to read image"); err != nil {
+		return nil, err
+	}
+	flipRows(dst)
+	return dst, nil
+}
+
+// ReadPixels wraps vgReadPixels, returning the width x height area of the
+// drawing surface at (x, y) with the top row first.
+func ReadPixels(x, y, width, height int) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, newError("failed to read pixels", IllegalArgumentError)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	C.vgReadPixels(
+		unsafe.Pointer(&dst.Pix[0]),
+		C.VGint(dst.Stride),
+		C.VGImageFormat(ImageFormatSabgr8888Pre),
+		C.VGint(x),
+		C.VGint(y),
+		C.VGint(width),
+		C.VGint(height),
+	)
+	if err := checkError("failed to read pixels"); err != nil {
+		return nil, err
+	}
+	flipRows(dst)
+	return dst, nil
+}
+
+// flipRows reverses the rows of img, converting between the bottom-up rows of
+// VG and the top-down rows of image.Image.
+func flipRows(img *image.RGBA) {
+	row := make([]byte, img.Stride)
+	for top, bottom := 0, img.Rect.Dy()-1; top < bottom; top, bottom = top+1, bottom-1 {
+		a := img.Pix[top*img.Stride : (top+1)*img.Stride]
+		b := img.Pix[bottom*img.Stride : (bottom+1)*img.Stride]
+		copy(row, a)
+		copy(a, b)
+		copy(b, row)
+	}
 }
 
 // Draw wraps vgDrawImage.
-func (img Image) Draw() {
+func (img Image) Draw() error {
 	C.vgDrawImage(img.handle)
+	return checkError("failed to draw image")
 }
 
-var errNames = map[C.VGErrorCode]string{
-	C.VG_NO_ERROR:                       "VG_NO_ERROR",
-	C.VG_BAD_HANDLE_ERROR:               "VG_BAD_HANDLE_ERROR",
-	C.VG_UNSUPPORTED_IMAGE_FORMAT_ERROR: "VG_UNSUPPORTED_IMAGE_FORMAT_ERROR",
-	C.VG_ILLEGAL_ARGUMENT_ERROR:         "VG_ILLEGAL_ARGUMENT_ERROR",
+// checkError returns the error reported by vgGetError for the operation op, or
+// nil.
+func checkError(op string) error {
+	if code := C.vgGetError(); code != C.VG_NO_ERROR {
+		return newError(op, ErrorCode(code))
+	}
+	return nil
+}
+
+// debugCheck checks vgGetError after a call without an error result when
+// debug mode is enabled, passing any error to the debug handler.
+func debugCheck(op string) {
+	if debugHandler != nil {
+		if code := C.vgGetError(); code != C.VG_NO_ERROR {
+			report(op, ErrorCode(code))
+		}
+	}
 }
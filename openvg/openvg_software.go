@@ -0,0 +1,395 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"unsafe"
+)
+
+// The software backend implements the subset of OpenVG used by this package
+// in pure Go, rendering into the image.RGBA bound with BindSurface. It follows
+// VG conventions: the origin is the bottom-left corner of the surface and row 0
+// of image data passed to Write is the bottom row of the image.
+
+// state holds what the VG context holds for the hardware backend.
+var state = struct {
+	surface *image.RGBA
+	params  map[ParamType][]float32
+	// mask is the drawing surface mask, by top-down rows; nil is all 1.
+	mask []float32
+	// The clipping parameters, cached for blendPixel by updateClip.
+	masking    bool
+	scissoring bool
+	scissor    []image.Rectangle
+}{
+	params: map[ParamType][]float32{
+		ParamMatrixMode:                {float32(MatrixPathUserToSurface)},
+		ParamFillRule:                  {float32(FillRuleEvenOdd)},
+		ParamImageQuality:              {float32(ImageQualityFaster)},
+		ParamRenderingQuality:          {float32(RenderingQualityBetter)},
+		ParamBlendMode:                 {float32(BlendSrcOver)},
+		ParamImageMode:                 {float32(DrawImageNormal)},
+		ParamScissorRects:              {},
+		ParamColorTransform:            {False},
+		ParamColorTransformValues:      {1, 1, 1, 1, 0, 0, 0, 0},
+		ParamStrokeLineWidth:           {1},
+		ParamStrokeCapStyle:            {float32(CapButt)},
+		ParamStrokeJoinStyle:           {float32(JoinMiter)},
+		ParamStrokeMiterLimit:          {4},
+		ParamStrokeDashPattern:         {},
+		ParamStrokeDashPhase:           {0},
+		ParamStrokeDashPhaseReset:      {False},
+		ParamTileFillColor:             {0, 0, 0, 0},
+		ParamClearColor:                {0, 0, 0, 0},
+		ParamGlyphOrigin:               {0, 0},
+		ParamMasking:                   {False},
+		ParamScissoring:                {False},
+		ParamPixelLayout:               {0}, // VG_PIXEL_LAYOUT_UNKNOWN
+		ParamScreenLayout:              {0},
+		ParamFilterFormatLinear:        {False},
+		ParamFilterFormatPremultiplied: {False},
+		ParamFilterChannelMask:         {0xF}, // VG_RED | VG_GREEN | VG_BLUE | VG_ALPHA
+		ParamMaxScissorRects:           {32},
+		ParamMaxDashCount:              {16},
+		ParamMaxKernelSize:             {7},
+		ParamMaxSeparableKernelSize:    {15},
+		ParamMaxColorRampStops:         {32},
+		ParamMaxImageWidth:             {4096},
+		ParamMaxImageHeight:            {4096},
+		ParamMaxImagePixels:            {4096 * 4096},
+		ParamMaxImageBytes:             {4096 * 4096 * 4},
+		ParamMaxFloat:                  {3.4028235e38},
+		ParamMaxGaussianStdDeviation:   {16},
+	},
+}
+
+// readOnlyParams are the implementation limits, which vgSet* rejects.
+var readOnlyParams = map[ParamType]bool{
+	ParamMaxScissorRects:         true,
+	ParamMaxDashCount:            true,
+	ParamMaxKernelSize:           true,
+	ParamMaxSeparableKernelSize:  true,
+	ParamMaxColorRampStops:       true,
+	ParamMaxImageWidth:           true,
+	ParamMaxImageHeight:          true,
+	ParamMaxImagePixels:          true,
+	ParamMaxImageBytes:           true,
+	ParamMaxFloat:                true,
+	ParamMaxGaussianStdDeviation: true,
+}
+
+// BindSurface makes surface the target of all drawing calls. The software egl
+// backend calls it from MakeCurrent.
+func BindSurface(surface *image.RGBA) {
+	if surface != state.surface {
+		state.mask = nil
+	}
+	state.surface = surface
+	updateClip()
+}
+
+// SetClearColor sets the color used by Clear, with components in [0, 1].
+func SetClearColor(r, g, b, a float32) error {
+	return Setfv(ParamClearColor, []float32{r, g, b, a})
+}
+
+// Setf wraps vgSetf.
+func Setf(param ParamType, value float32) error {
+	return Setfv(param, []float32{value})
+}
+
+// Seti wraps vgSeti.
+func Seti(param ParamType, value int) error {
+	return Setfv(param, []float32{float32(value)})
+}
+
+// Setfv wraps vgSetfv.
+func Setfv(param ParamType, values []float32) error {
+	old, ok := state.params[param]
+	if !ok || readOnlyParams[param] {
+		return newError(fmt.Sprintf("failed to set parameter %#x", int(param)), IllegalArgumentError)
+	}
+	// Only the variable-length parameters accept a different number of values.
+	switch param {
+	case ParamScissorRects, ParamStrokeDashPattern:
+	default:
+		if len(values) != len(old) {
+			return newError(fmt.Sprintf("failed to set parameter %#x", int(param)), IllegalArgumentError)
+		}
+	}
+	state.params[param] = append([]float32(nil), values...)
+	updateClip()
+	return nil
+}
+
+// Getf wraps vgGetf.
+func Getf(param ParamType) float32 {
+	v, ok := state.params[param]
+	if !ok {
+		report("failed to get parameter", IllegalArgumentError)
+	}
+	if len(v) > 0 {
+		return v[0]
+	}
+	return 0
+}
+
+// Geti wraps vgGeti.
+func Geti(param ParamType) int {
+	return int(math.Floor(float64(Getf(param))))
+}
+
+// Getfv wraps vgGetVectorSize and vgGetfv.
+func Getfv(param ParamType) []float32 {
+	v, ok := state.params[param]
+	if !ok {
+		report("failed to get parameter", IllegalArgumentError)
+	}
+	return append([]float32(nil), v...)
+}
+
+// paramColor returns the RGBA color held by param.
+func paramColor(param ParamType) color.RGBA {
+	v := state.params[param]
+	return color.RGBAModel.Convert(color.NRGBA{
+		unitToByte(v[0]), unitToByte(v[1]), unitToByte(v[2]), unitToByte(v[3]),
+	}).(color.RGBA)
+}
+
+// unitToByte clamps v to [0, 1] and scales it to [0, 255].
+func unitToByte(v float32) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 1 {
+		return 0xff
+	}
+	return uint8(v*0xff + 0.5)
+}
+
+// Clear wraps vgClear.
+func Clear(x, y, w, h int) error {
+	if w <= 0 || h <= 0 {
+		return newError("failed to clear", IllegalArgumentError)
+	}
+	if state.surface == nil {
+		return nil
+	}
+	r := vgRect(state.surface.Bounds(), x, y, w, h)
+	c := paramColor(ParamClearColor)
+	for sy := r.Min.Y; sy < r.Max.Y; sy++ {
+		for sx := r.Min.X; sx < r.Max.X; sx++ {
+			// Unlike drawing, clearing ignores the mask.
+			if state.scissoring && !inScissor(sx, sy) {
+				continue
+			}
+			state.surface.SetRGBA(sx, sy, c)
+		}
+	}
+	return nil
+}
+
+// vgRect converts a rectangle in VG coordinates, whose origin is the
+// bottom-left corner, into the top-down coordinates of bounds, clipped to
+// bounds.
+func vgRect(bounds image.Rectangle, x, y, w, h int) image.Rectangle {
+	top := bounds.Max.Y - (y + h)
+	return image.Rect(bounds.Min.X+x, top, bounds.Min.X+x+w, top+h).Intersect(bounds)
+}
+
+type ImageQuality int
+
+const (
+	ImageQualityNonantialiased = ImageQuality(1 << 0)
+	ImageQualityFaster         = ImageQuality(1 << 1)
+	ImageQualityBetter         = ImageQuality(1 << 2)
+)
+
+type Image struct {
+	handle *softImage
+}
+
+type softImage struct {
+	pix       *image.RGBA
+	format    ImageFormat
+	destroyed bool
+}
+
+func CreateImage(format ImageFormat, width, height int, quality []ImageQuality) (Image, error) {
+	if !format.valid() {
+		return Image{}, newError("failed to create image", UnsupportedImageFormatError)
+	}
+	if width <= 0 || height <= 0 {
+		return Image{}, newError("failed to create image", IllegalArgumentError)
+	}
+	pix := image.NewRGBA(image.Rect(0, 0, width, height))
+	// Images start transparent black, which formats without alpha hold as
+	// opaque black.
+	if c := format.normalize(color.RGBA{}); c != (color.RGBA{}) {
+		for i := 0; i < len(pix.Pix); i += 4 {
+			pix.Pix[i], pix.Pix[i+1], pix.Pix[i+2], pix.Pix[i+3] = c.R, c.G, c.B, c.A
+		}
+	}
+	return Image{&softImage{pix: pix, format: format}}, nil
+}
+
+// ClientBufferHandle holds the pixels of an image, which the software egl
+// backend binds as the drawing surface of a pbuffer created from the image.
+type ClientBufferHandle struct {
+	Image *image.RGBA
+}
+
+// ClientBuffer returns the client buffer handle of img, through which egl
+// creates a pbuffer surface that renders into img. Its Image is nil if img has
+// been destroyed.
+func (img Image) ClientBuffer() ClientBufferHandle {
+	if img.handle == nil || img.handle.destroyed {
+		return ClientBufferHandle{}
+	}
+	return ClientBufferHandle{img.handle.pix}
+}
+
+// Destroy wraps vgDestroyImage. It returns ErrLiveChildren, leaving img
+// alive, if img has child images that have not been destroyed.
+func (img Image) Destroy() error {
+	if img.handle == nil || img.handle.destroyed {
+		return newError("failed to destroy image", BadHandleError)
+	}
+	if err := checkDestroy(img); err != nil {
+		return err
+	}
+	img.handle.destroyed = true
+	img.handle.pix = nil
+	forgetImage(img)
+	return nil
+}
+
+// Write calls vgImageSubData.
+func (img Image) Write(p unsafe.Pointer, stride int, fmt ImageFormat, x, y, width, height int) error {
+	if img.handle == nil || img.handle.destroyed {
+		return newError("failed to write image", BadHandleError)
+	}
+	if !fmt.valid() {
+		return newError("failed to write image", UnsupportedImageFormatError)
+	}
+	if p == nil || width <= 0 || height <= 0 {
+		return newError("failed to write image", IllegalArgumentError)
+	}
+	dst := img.handle.pix
+	r := vgRect(dst.Bounds(), x, y, width, height)
+	for row := 0; row < height; row++ {
+		// Row 0 of the data is the bottom row of the written area.
+		dy := dst.Bounds().Max.Y - 1 - (y + row)
+		if dy < r.Min.Y || dy >= r.Max.Y {
+			continue
+		}
+		src := unsafe.Pointer(uintptr(p) + uintptr(row*stride))
+		for col := 0; col < width; col++ {
+			dx := x + col
+			if dx < r.Min.X || dx >= r.Max.X {
+				continue
+			}
+			dst.SetRGBA(dx, dy, fmt.pixel(src, col))
+		}
+	}
+	return nil
+}
+
+// Format wraps vgGetParameteri(VG_IMAGE_FORMAT).
+func (img Image) Format() ImageFormat {
+	if img.handle == nil || img.handle.destroyed {
+		report("failed to get image parameter", BadHandleError)
+		return 0
+	}
+	return img.handle.format
+}
+
+// Width wraps vgGetParameteri(VG_IMAGE_WIDTH).
+func (img Image) Width() int {
+	if img.handle == nil || img.handle.destroyed {
+		report("failed to get image parameter", BadHandleError)
+		return 0
+	}
+	return img.handle.pix.Bounds().Dx()
+}
+
+// Height wraps vgGetParameteri(VG_IMAGE_HEIGHT).
+func (img Image) Height() int {
+	if img.handle == nil || img.handle.destroyed {
+		report("failed to get image parameter", BadHandleError)
+		return 0
+	}
+	return img.handle.pix.Bounds().Dy()
+}
+
+// Read wraps vgGetImageSubData, returning the width x height area of img at
+// (x, y) with the top row first.
+func (img Image) Read(x, y, width, height int) (*image.RGBA, error) {
+	if img.handle == nil || img.handle.destroyed {
+		return nil, newError("failed to read image", BadHandleError)
+	}
+	if width <= 0 || height <= 0 {
+		return nil, newError("failed to read image", IllegalArgumentError)
+	}
+	return readRect(img.handle.pix, x, y, width, height), nil
+}
+
+// ReadPixels wraps vgReadPixels, returning the width x height area of the
+// drawing surface at (x, y) with the top row first.
+func ReadPixels(x, y, width, height int) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, newError("failed to read pixels", IllegalArgumentError)
+	}
+	if state.surface == nil {
+		return image.NewRGBA(image.Rect(0, 0, width, height)), nil
+	}
+	return readRect(state.surface, x, y, width, height), nil
+}
+
+// readRect copies the area of src at (x, y) in VG coordinates. As in VG,
+// pixels outside src are left untouched, which here means transparent black.
+func readRect(src *image.RGBA, x, y, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	r := vgRect(src.Bounds(), x, y, width, height)
+	top := src.Bounds().Max.Y - (y + height)
+	for sy := r.Min.Y; sy < r.Max.Y; sy++ {
+		copy(dst.Pix[dst.PixOffset(r.Min.X-src.Bounds().Min.X-x, sy-top):], src.Pix[src.PixOffset(r.Min.X, sy):src.PixOffset(r.Max.X, sy)])
+	}
+	return dst
+}
+
+// Draw wraps vgDrawImage. Each surface pixel is mapped back through the
+// image-user-to-surface matrix and sampled from the image, nearest neighbour.
+func (img Image) Draw() error {
+	if img.handle == nil || img.handle.destroyed {
+		return newError("failed to draw image", BadHandleError)
+	}
+	if state.surface == nil {
+		return nil
+	}
+	src := img.handle.pix
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	m := matrices[MatrixImageUserToSurface]
+	inv, ok := m.Invert()
+	if !ok {
+		return nil
+	}
+	sh := state.surface.Bounds().Dy()
+	r := surfaceBounds(m, float32(w), float32(h))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			u, v := inv.Apply(float32(x)+0.5, float32(sh-y)-0.5)
+			if u < 0 || v < 0 || u >= float32(w) || v >= float32(h) {
+				continue
+			}
+			c := src.RGBAAt(int(u), h-1-int(v))
+			blendPixel(x, y, toPremul(c), 1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+	"unsafe"
+)
+
+// bindTestSurface binds a new transparent width x height drawing surface and
+// restores the parameters, matrices and paints when the test ends.
+func bindTestSurface(t *testing.T, width, height int) *image.RGBA {
+	t.Helper()
+	params := make(map[ParamType][]float32, len(state.params))
+	for k, v := range state.params {
+		params[k] = v
+	}
+	savedMatrices := make(map[MatrixMode]Matrix, len(matrices))
+	for k, v := range matrices {
+		savedMatrices[k] = v
+	}
+	savedPaints := make(map[PaintMode]*softPaint, len(paints))
+	for k, v := range paints {
+		savedPaints[k] = v
+	}
+	t.Cleanup(func() {
+		state.params = params
+		matrices = savedMatrices
+		paints = savedPaints
+		BindSurface(nil)
+	})
+	surface := image.NewRGBA(image.Rect(0, 0, width, height))
+	BindSurface(surface)
+	return surface
+}
+
+// checkPixel reports an error if the pixel of surface at (x, y), in VG
+// coordinates, is not want.
+func checkPixel(t *testing.T, surface *image.RGBA, x, y int, want color.RGBA) {
+	t.Helper()
+	if got := surface.RGBAAt(x, surface.Bounds().Dy()-1-y); got != want {
+		t.Errorf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+	}
+}
+
+var (
+	red         = color.RGBA{0xff, 0, 0, 0xff}
+	green       = color.RGBA{0, 0xff, 0, 0xff}
+	blue        = color.RGBA{0, 0, 0xff, 0xff}
+	white       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	transparent = color.RGBA{}
+)
+
+func TestClear(t *testing.T) {
+	surface := bindTestSurface(t, 4, 3)
+	if err := SetClearColor(1, 0, 0, 1); err != nil {
+		t.Fatalf("SetClearColor: %v", err)
+	}
+	if err := Clear(0, 0, 4, 3); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	SetClearColor(0, 0, 1, 1)
+	// Clear counts y from the bottom row and clips to the surface.
+	if err := Clear(2, 1, 5, 1); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			want := red
+			if y == 1 && x >= 2 {
+				want = blue
+			}
+			checkPixel(t, surface, x, y, want)
+		}
+	}
+
+	if err := Clear(0, 0, 0, 1); !errors.Is(err, ErrIllegalArgument) {
+		t.Errorf("Clear of an empty area returned %v, want %v", err, ErrIllegalArgument)
+	}
+}
+
+func TestClearScissored(t *testing.T) {
+	surface := bindTestSurface(t, 4, 4)
+	SetScissorRects([]PixelRect{{X: 1, Y: 1, Width: 2, Height: 2}})
+	SetScissoring(true)
+	SetClearColor(1, 1, 1, 1)
+	if err := Clear(0, 0, 4, 4); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	checkPixel(t, surface, 0, 0, transparent)
+	checkPixel(t, surface, 1, 1, white)
+	checkPixel(t, surface, 2, 2, white)
+	checkPixel(t, surface, 3, 3, transparent)
+}
+
+// writeTestImage returns a 2x2 sRGBA_8888 image with red and green in the
+// bottom row and blue and white in the top row.
+func writeTestImage(t *testing.T) Image {
+	t.Helper()
+	img, err := CreateImage(ImageFormatSrgba8888, 2, 2, []ImageQuality{ImageQualityNonantialiased})
+	if err != nil {
+		t.Fatalf("CreateImage: %v", err)
+	}
+	t.Cleanup(func() { img.Destroy() })
+	// Row 0 of the data is the bottom row.
+	data := []uint32{
+		0xff0000ff, 0x00ff00ff,
+		0x0000ffff, 0xffffffff,
+	}
+	if err := img.Write(unsafe.Pointer(&data[0]), 8, ImageFormatSrgba8888, 0, 0, 2, 2); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return img
+}
+
+func TestImageWrite(t *testing.T) {
+	img := writeTestImage(t)
+	got, err := img.Read(0, 0, 2, 2)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	// Read returns the top row first.
+	for _, p := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{0, 0, blue}, {1, 0, white},
+		{0, 1, red}, {1, 1, green},
+	} {
+		if c := got.RGBAAt(p.x, p.y); c != p.want {
+			t.Errorf("Read pixel (%d, %d) = %v, want %v", p.x, p.y, c, p.want)
+		}
+	}
+
+	// Writes outside the image are clipped.
+	data := []uint32{0x000000ff, 0x000000ff}
+	if err := img.Write(unsafe.Pointer(&data[0]), 8, ImageFormatSrgba8888, 1, 1, 2, 1); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got, _ := img.Read(0, 1, 2, 1); got.RGBAAt(0, 0) != blue || got.RGBAAt(1, 0) != (color.RGBA{0, 0, 0, 0xff}) {
+		t.Errorf("top row after clipped write = %v, %v", got.RGBAAt(0, 0), got.RGBAAt(1, 0))
+	}
+
+	if err := img.Write(nil, 8, ImageFormatSrgba8888, 0, 0, 2, 2); !errors.Is(err, ErrIllegalArgument) {
+		t.Errorf("Write of nil data returned %v, want %v", err, ErrIllegalArgument)
+	}
+}
+
+func TestImageDraw(t *testing.T) {
+	surface := bindTestSurface(t, 4, 4)
+	img := writeTestImage(t)
+	SetMatrixMode(MatrixImageUserToSurface)
+
+	LoadIdentity()
+	if err := img.Draw(); err != nil {
+		t.Fatalf("Draw: %v", err)
+	}
+	checkPixel(t, surface, 0, 0, red)
+	checkPixel(t, surface, 1, 0, green)
+	checkPixel(t, surface, 0, 1, blue)
+	checkPixel(t, surface, 1, 1, white)
+	checkPixel(t, surface, 2, 2, transparent)
+
+	// The image matrix places the image on the surface.
+	Translate(2, 2)
+	if err := img.Draw(); err != nil {
+		t.Fatalf("Draw: %v", err)
+	}
+	checkPixel(t, surface, 2, 2, red)
+	checkPixel(t, surface, 3, 3, white)
+
+	LoadIdentity()
+	Scale(2, 2)
+	if err := img.Draw(); err != nil {
+		t.Fatalf("Draw: %v", err)
+	}
+	checkPixel(t, surface, 1, 1, red)
+	checkPixel(t, surface, 2, 1, green)
+	checkPixel(t, surface, 3, 3, white)
+
+	if err := img.Destroy(); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if err := img.Draw(); !errors.Is(err, ErrBadHandle) {
+		t.Errorf("Draw of a destroyed image returned %v, want %v", err, ErrBadHandle)
+	}
+}
+
+func TestDrawBlendsOverSurface(t *testing.T) {
+	surface := bindTestSurface(t, 1, 1)
+	SetClearColor(0, 0, 1, 1)
+	Clear(0, 0, 1, 1)
+	img, err := CreateImage(ImageFormatSrgba8888, 1, 1, nil)
+	if err != nil {
+		t.Fatalf("CreateImage: %v", err)
+	}
+	defer img.Destroy()
+	// Half-transparent red.
+	data := []uint32{0xff000080}
+	img.Write(unsafe.Pointer(&data[0]), 4, ImageFormatSrgba8888, 0, 0, 1, 1)
+	SetMatrixMode(MatrixImageUserToSurface)
+	LoadIdentity()
+	if err := img.Draw(); err != nil {
+		t.Fatalf("Draw: %v", err)
+	}
+	got := surface.RGBAAt(0, 0)
+	if got.A != 0xff || got.R < 0x7e || got.R > 0x81 || got.B < 0x7e || got.B > 0x81 || got.G != 0 {
+		t.Errorf("red at half alpha over blue = %v, want about {128 0 127 255}", got)
+	}
+}
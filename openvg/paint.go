@@ -0,0 +1,100 @@
+//go:build !software
+// +build !software
+
+package openvg
+
+// #include "VG/openvg.h"
+import "C"
+import "unsafe"
+
+// Paint wraps a VGPaint. The zero Paint is VG_INVALID_HANDLE, which Set
+// interprets as the default paint, opaque black.
+type Paint struct {
+	handle C.VGPaint
+}
+
+// CreatePaint wraps vgCreatePaint.
+func CreatePaint() (Paint, error) {
+	handle := C.vgCreatePaint()
+	if handle == C.VG_INVALID_HANDLE {
+		return Paint{}, newError("failed to create paint", ErrorCode(C.vgGetError()))
+	}
+	return Paint{handle}, nil
+}
+
+// NewColorPaint creates a paint of a single non-premultiplied color.
+func NewColorPaint(r, g, b, a float32) (Paint, error) {
+	p, err := CreatePaint()
+	if err != nil {
+		return Paint{}, err
+	}
+	if err := p.SetColor(r, g, b, a); err != nil {
+		p.Destroy()
+		return Paint{}, err
+	}
+	return p, nil
+}
+
+// Destroy wraps vgDestroyPaint.
+func (p Paint) Destroy() error {
+	C.vgDestroyPaint(p.handle)
+	return checkError("failed to destroy paint")
+}
+
+// Set wraps vgSetPaint, making p the paint used by Path.Draw for the given
+// modes.
+func (p Paint) Set(modes PaintMode) error {
+	C.vgSetPaint(p.handle, C.VGbitfield(modes))
+	return checkError("failed to set paint")
+}
+
+// SetColor makes p a solid color paint of the given non-premultiplied color.
+func (p Paint) SetColor(r, g, b, a float32) error {
+	p.seti(C.VG_PAINT_TYPE, C.VG_PAINT_TYPE_COLOR)
+	p.setfv(C.VG_PAINT_COLOR, []float32{r, g, b, a})
+	return checkError("failed to set paint color")
+}
+
+// SetLinearGradient makes p a linear gradient from (x0, y0) to (x1, y1), in
+// paint coordinates.
+func (p Paint) SetLinearGradient(x0, y0, x1, y1 float32, stops []GradientStop, spread ColorRampSpreadMode) error {
+	p.seti(C.VG_PAINT_TYPE, C.VG_PAINT_TYPE_LINEAR_GRADIENT)
+	p.setfv(C.VG_PAINT_LINEAR_GRADIENT, []float32{x0, y0, x1, y1})
+	p.setRamp(stops, spread)
+	return checkError("failed to set linear gradient")
+}
+
+// SetRadialGradient makes p a radial gradient of the circle centered at
+// (cx, cy) with radius r and focal point (fx, fy), in paint coordinates.
+func (p Paint) SetRadialGradient(cx, cy, fx, fy, r float32, stops []GradientStop, spread ColorRampSpreadMode) error {
+	p.seti(C.VG_PAINT_TYPE, C.VG_PAINT_TYPE_RADIAL_GRADIENT)
+	p.setfv(C.VG_PAINT_RADIAL_GRADIENT, []float32{cx, cy, fx, fy, r})
+	p.setRamp(stops, spread)
+	return checkError("failed to set radial gradient")
+}
+
+// SetPattern wraps vgPaintPattern, making p a pattern of img. The image must
+// not be drawn to or destroyed while the paint uses it.
+func (p Paint) SetPattern(img Image, tiling TilingMode) error {
+	p.seti(C.VG_PAINT_TYPE, C.VG_PAINT_TYPE_PATTERN)
+	p.seti(C.VG_PAINT_PATTERN_TILING_MODE, C.VGint(tiling))
+	C.vgPaintPattern(p.handle, img.handle)
+	return checkError("failed to set pattern")
+}
+
+func (p Paint) setRamp(stops []GradientStop, spread ColorRampSpreadMode) {
+	p.seti(C.VG_PAINT_COLOR_RAMP_SPREAD_MODE, C.VGint(spread))
+	p.setfv(C.VG_PAINT_COLOR_RAMP_STOPS, rampStops(stops))
+}
+
+func (p Paint) seti(param C.VGint, value C.VGint) {
+	C.vgSetParameteri(C.VGHandle(p.handle), param, value)
+}
+
+func (p Paint) setfv(param C.VGint, values []float32) {
+	var v *C.VGfloat
+	if len(values) > 0 {
+		v = (*C.VGfloat)(unsafe.Pointer(&values[0]))
+	}
+	C.vgSetParameterfv(C.VGHandle(p.handle), param, C.VGint(len(values)), v)
+}
@@ -0,0 +1,289 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"math"
+	"sort"
+)
+
+// Paint wraps a VGPaint. The zero Paint is VG_INVALID_HANDLE, which Set
+// interprets as the default paint, opaque black.
+type Paint struct {
+	handle *softPaint
+}
+
+type paintType int
+
+const (
+	paintColor paintType = iota
+	paintLinear
+	paintRadial
+	paintPattern
+)
+
+type softPaint struct {
+	kind      paintType
+	color     premul
+	linear    [4]float32
+	radial    [5]float32
+	stops     []GradientStop
+	spread    ColorRampSpreadMode
+	pattern   Image
+	tiling    TilingMode
+	destroyed bool
+}
+
+// paints holds the paint set for each paint mode; nil is the default paint.
+var paints = map[PaintMode]*softPaint{}
+
+func (p Paint) valid() bool {
+	return p.handle != nil && !p.handle.destroyed
+}
+
+// CreatePaint wraps vgCreatePaint.
+func CreatePaint() (Paint, error) {
+	return Paint{&softPaint{color: premul{0, 0, 0, 1}, spread: SpreadPad, tiling: TileFill}}, nil
+}
+
+// NewColorPaint creates a paint of a single non-premultiplied color.
+func NewColorPaint(r, g, b, a float32) (Paint, error) {
+	p, err := CreatePaint()
+	if err != nil {
+		return Paint{}, err
+	}
+	if err := p.SetColor(r, g, b, a); err != nil {
+		p.Destroy()
+		return Paint{}, err
+	}
+	return p, nil
+}
+
+// Destroy wraps vgDestroyPaint.
+func (p Paint) Destroy() error {
+	if !p.valid() {
+		return newError("failed to destroy paint", BadHandleError)
+	}
+	// Like VG, a paint that is set stays in effect until replaced.
+	p.handle.destroyed = true
+	return nil
+}
+
+// Set wraps vgSetPaint, making p the paint used by Path.Draw for the given
+// modes.
+func (p Paint) Set(modes PaintMode) error {
+	if p.handle != nil && p.handle.destroyed {
+		return newError("failed to set paint", BadHandleError)
+	}
+	if modes&^(FillPath|StrokePath) != 0 || modes == 0 {
+		return newError("failed to set paint", IllegalArgumentError)
+	}
+	for _, mode := range []PaintMode{FillPath, StrokePath} {
+		if modes&mode != 0 {
+			paints[mode] = p.handle
+		}
+	}
+	return nil
+}
+
+// SetColor makes p a solid color paint of the given non-premultiplied color.
+func (p Paint) SetColor(r, g, b, a float32) error {
+	if !p.valid() {
+		return newError("failed to set paint color", BadHandleError)
+	}
+	p.handle.kind = paintColor
+	a = clampUnit(a)
+	p.handle.color = premul{clampUnit(r) * a, clampUnit(g) * a, clampUnit(b) * a, a}
+	return nil
+}
+
+// SetLinearGradient makes p a linear gradient from (x0, y0) to (x1, y1), in
+// paint coordinates.
+func (p Paint) SetLinearGradient(x0, y0, x1, y1 float32, stops []GradientStop, spread ColorRampSpreadMode) error {
+	if !p.valid() {
+		return newError("failed to set linear gradient", BadHandleError)
+	}
+	p.handle.kind = paintLinear
+	p.handle.linear = [4]float32{x0, y0, x1, y1}
+	p.handle.setRamp(stops, spread)
+	return nil
+}
+
+// SetRadialGradient makes p a radial gradient of the circle centered at
+// (cx, cy) with radius r and focal point (fx, fy), in paint coordinates.
+func (p Paint) SetRadialGradient(cx, cy, fx, fy, r float32, stops []GradientStop, spread ColorRampSpreadMode) error {
+	if !p.valid() {
+		return newError("failed to set radial gradient", BadHandleError)
+	}
+	p.handle.kind = paintRadial
+	p.handle.radial = [5]float32{cx, cy, fx, fy, r}
+	p.handle.setRamp(stops, spread)
+	return nil
+}
+
+// SetPattern wraps vgPaintPattern, making p a pattern of img. The image must
+// not be drawn to or destroyed while the paint uses it.
+func (p Paint) SetPattern(img Image, tiling TilingMode) error {
+	if !p.valid() || img.handle == nil || img.handle.destroyed {
+		return newError("failed to set pattern", BadHandleError)
+	}
+	p.handle.kind = paintPattern
+	p.handle.pattern = img
+	p.handle.tiling = tiling
+	return nil
+}
+
+// setRamp stores the valid stops in order, as VG does: stops with offsets
+// outside [0, 1] or out of order are ignored.
+func (p *softPaint) setRamp(stops []GradientStop, spread ColorRampSpreadMode) {
+	p.spread = spread
+	p.stops = p.stops[:0]
+	last := float32(-1)
+	for _, s := range stops {
+		if s.Offset < 0 || s.Offset > 1 || s.Offset < last {
+			continue
+		}
+		last = s.Offset
+		p.stops = append(p.stops, s)
+	}
+}
+
+// paintAt returns the color of the paint for mode at the surface point
+// (x, y).
+func paintAt(mode PaintMode, x, y float32) premul {
+	p := paints[mode]
+	if p == nil || p.kind == paintColor {
+		if p == nil {
+			return premul{0, 0, 0, 1}
+		}
+		return p.color
+	}
+	// Map the surface point back into paint coordinates.
+	paintToUser := matrices[MatrixFillPaintToUser]
+	if mode == StrokePath {
+		paintToUser = matrices[MatrixStrokePaintToUser]
+	}
+	inv, ok := matrices[MatrixPathUserToSurface].Mul(paintToUser).Invert()
+	if !ok {
+		return premul{}
+	}
+	u, v := inv.Apply(x, y)
+	switch p.kind {
+	case paintLinear:
+		x0, y0, x1, y1 := p.linear[0], p.linear[1], p.linear[2], p.linear[3]
+		dx, dy := x1-x0, y1-y0
+		d := dx*dx + dy*dy
+		if d == 0 {
+			return p.ramp(1)
+		}
+		return p.ramp(((u-x0)*dx + (v-y0)*dy) / d)
+	case paintRadial:
+		return p.ramp(p.radialT(u, v))
+	case paintPattern:
+		return p.patternAt(u, v)
+	}
+	return p.color
+}
+
+// radialT evaluates the VG radial gradient function at (u, v).
+func (p *softPaint) radialT(u, v float32) float32 {
+	cx, cy, fx, fy, r := p.radial[0], p.radial[1], p.radial[2], p.radial[3], p.radial[4]
+	if r <= 0 {
+		return 1
+	}
+	// A focal point outside the circle is moved just inside it.
+	fpx, fpy := fx-cx, fy-cy
+	if d := float32(math.Hypot(float64(fpx), float64(fpy))); d > r*0.99 {
+		fpx, fpy = fpx*r*0.99/d, fpy*r*0.99/d
+	}
+	dx, dy := u-cx-fpx, v-cy-fpy
+	den := r*r - (fpx*fpx + fpy*fpy)
+	cross := dx*fpy - dy*fpx
+	root := float32(math.Sqrt(math.Max(0, float64(r*r*(dx*dx+dy*dy)-cross*cross))))
+	return ((dx*fpx + dy*fpy) + root) / den
+}
+
+// ramp returns the premultiplied ramp color at t after applying the spread
+// mode. Without stops the ramp runs from opaque black to opaque white.
+func (p *softPaint) ramp(t float32) premul {
+	switch p.spread {
+	case SpreadRepeat:
+		t -= float32(math.Floor(float64(t)))
+	case SpreadReflect:
+		t = float32(math.Abs(math.Mod(float64(t), 2)))
+		if t > 1 {
+			t = 2 - t
+		}
+	default:
+		t = clampUnit(t)
+	}
+	stops := p.stops
+	if len(stops) == 0 {
+		stops = []GradientStop{{0, 0, 0, 0, 1}, {1, 1, 1, 1, 1}}
+	}
+	i := sort.Search(len(stops), func(i int) bool { return stops[i].Offset >= t })
+	if i == 0 {
+		return stopColor(stops[0])
+	}
+	if i == len(stops) {
+		return stopColor(stops[len(stops)-1])
+	}
+	a, b := stops[i-1], stops[i]
+	if b.Offset == a.Offset {
+		return stopColor(b)
+	}
+	k := (t - a.Offset) / (b.Offset - a.Offset)
+	ca, cb := stopColor(a), stopColor(b)
+	return premul{
+		ca.r + (cb.r-ca.r)*k,
+		ca.g + (cb.g-ca.g)*k,
+		ca.b + (cb.b-ca.b)*k,
+		ca.a + (cb.a-ca.a)*k,
+	}
+}
+
+func stopColor(s GradientStop) premul {
+	a := clampUnit(s.A)
+	return premul{clampUnit(s.R) * a, clampUnit(s.G) * a, clampUnit(s.B) * a, a}
+}
+
+// patternAt samples the pattern image at (u, v) in paint coordinates.
+func (p *softPaint) patternAt(u, v float32) premul {
+	img := p.pattern.handle
+	if img == nil || img.destroyed {
+		return premul{0, 0, 0, 1}
+	}
+	w, h := float32(img.pix.Bounds().Dx()), float32(img.pix.Bounds().Dy())
+	tile := func(c, size float32) (float32, bool) {
+		switch p.tiling {
+		case TilePad:
+			return float32(math.Min(math.Max(float64(c), 0), float64(size)-0.5)), true
+		case TileRepeat:
+			return c - size*float32(math.Floor(float64(c/size))), true
+		case TileReflect:
+			m := float32(math.Mod(float64(c), float64(2*size)))
+			if m < 0 {
+				m += 2 * size
+			}
+			if m >= size {
+				m = 2*size - m - 0.5
+			}
+			return m, true
+		default:
+			return c, c >= 0 && c < size
+		}
+	}
+	tu, okU := tile(u, w)
+	tv, okV := tile(v, h)
+	if !okU || !okV {
+		c := Getfv(ParamTileFillColor)
+		a := clampUnit(c[3])
+		return premul{clampUnit(c[0]) * a, clampUnit(c[1]) * a, clampUnit(c[2]) * a, a}
+	}
+	return toPremul(img.pix.RGBAAt(int(tu), img.pix.Bounds().Dy()-1-int(tv)))
+}
+
+func clampUnit(v float32) float32 {
+	return float32(math.Min(math.Max(float64(v), 0), 1))
+}
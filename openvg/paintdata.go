@@ -0,0 +1,39 @@
+package openvg
+
+// ColorRampSpreadMode represents a VGColorRampSpreadMode, which selects how a
+// gradient continues beyond its end points.
+type ColorRampSpreadMode int
+
+const (
+	SpreadPad     = ColorRampSpreadMode(0x1C00)
+	SpreadRepeat  = ColorRampSpreadMode(0x1C01)
+	SpreadReflect = ColorRampSpreadMode(0x1C02)
+)
+
+// TilingMode represents a VGTilingMode, which selects how a pattern paint is
+// extended beyond its image.
+type TilingMode int
+
+const (
+	TileFill    = TilingMode(0x1D00)
+	TilePad     = TilingMode(0x1D01)
+	TileRepeat  = TilingMode(0x1D02)
+	TileReflect = TilingMode(0x1D03)
+)
+
+// GradientStop is one entry of a gradient color ramp. Offset is in [0, 1]
+// and the color components are non-premultiplied, in [0, 1].
+type GradientStop struct {
+	Offset     float32
+	R, G, B, A float32
+}
+
+// rampStops flattens stops into the {offset, r, g, b, a, ...} layout of
+// VG_PAINT_COLOR_RAMP_STOPS.
+func rampStops(stops []GradientStop) []float32 {
+	values := make([]float32, 0, 5*len(stops))
+	for _, s := range stops {
+		values = append(values, s.Offset, s.R, s.G, s.B, s.A)
+	}
+	return values
+}
@@ -0,0 +1,127 @@
+package openvg
+
+// ParamType represents a VGParamType, the key of a piece of context state set
+// with Setf, Seti and Setfv.
+type ParamType int
+
+// Values from VGParamType.
+const (
+	// Mode settings.
+	ParamMatrixMode       = ParamType(0x1100)
+	ParamFillRule         = ParamType(0x1101)
+	ParamImageQuality     = ParamType(0x1102)
+	ParamRenderingQuality = ParamType(0x1103)
+	ParamBlendMode        = ParamType(0x1104)
+	ParamImageMode        = ParamType(0x1105)
+
+	// Scissoring rectangles.
+	ParamScissorRects = ParamType(0x1106)
+
+	// Color transformation.
+	ParamColorTransform       = ParamType(0x1170)
+	ParamColorTransformValues = ParamType(0x1171)
+
+	// Stroke parameters.
+	ParamStrokeLineWidth      = ParamType(0x1110)
+	ParamStrokeCapStyle       = ParamType(0x1111)
+	ParamStrokeJoinStyle      = ParamType(0x1112)
+	ParamStrokeMiterLimit     = ParamType(0x1113)
+	ParamStrokeDashPattern    = ParamType(0x1114)
+	ParamStrokeDashPhase      = ParamType(0x1115)
+	ParamStrokeDashPhaseReset = ParamType(0x1116)
+
+	// Edge fill color for tiled images and the color used by Clear.
+	ParamTileFillColor = ParamType(0x1120)
+	ParamClearColor    = ParamType(0x1121)
+
+	// Glyph origin.
+	ParamGlyphOrigin = ParamType(0x1122)
+
+	// Enable/disable alpha masking and scissoring.
+	ParamMasking    = ParamType(0x1130)
+	ParamScissoring = ParamType(0x1131)
+
+	// Pixel layout information.
+	ParamPixelLayout  = ParamType(0x1140)
+	ParamScreenLayout = ParamType(0x1141)
+
+	// Source format selection for image filters.
+	ParamFilterFormatLinear        = ParamType(0x1150)
+	ParamFilterFormatPremultiplied = ParamType(0x1151)
+
+	// Destination write enable mask for image filters.
+	ParamFilterChannelMask = ParamType(0x1152)
+
+	// Implementation limits (read-only).
+	ParamMaxScissorRects         = ParamType(0x1160)
+	ParamMaxDashCount            = ParamType(0x1161)
+	ParamMaxKernelSize           = ParamType(0x1162)
+	ParamMaxSeparableKernelSize  = ParamType(0x1163)
+	ParamMaxColorRampStops       = ParamType(0x1164)
+	ParamMaxImageWidth           = ParamType(0x1165)
+	ParamMaxImageHeight          = ParamType(0x1166)
+	ParamMaxImagePixels          = ParamType(0x1167)
+	ParamMaxImageBytes           = ParamType(0x1168)
+	ParamMaxFloat                = ParamType(0x1169)
+	ParamMaxGaussianStdDeviation = ParamType(0x116A)
+)
+
+// MatrixMode represents a VGMatrixMode, the value of ParamMatrixMode.
+type MatrixMode int
+
+const (
+	MatrixPathUserToSurface  = MatrixMode(0x1400)
+	MatrixImageUserToSurface = MatrixMode(0x1401)
+	MatrixFillPaintToUser    = MatrixMode(0x1402)
+	MatrixStrokePaintToUser  = MatrixMode(0x1403)
+	MatrixGlyphUserToSurface = MatrixMode(0x1404)
+)
+
+// FillRule represents a VGFillRule, the value of ParamFillRule.
+type FillRule int
+
+const (
+	FillRuleEvenOdd = FillRule(0x1900)
+	FillRuleNonZero = FillRule(0x1901)
+)
+
+// RenderingQuality represents a VGRenderingQuality, the value of
+// ParamRenderingQuality.
+type RenderingQuality int
+
+const (
+	RenderingQualityNonantialiased = RenderingQuality(0x1200)
+	RenderingQualityFaster         = RenderingQuality(0x1201)
+	RenderingQualityBetter         = RenderingQuality(0x1202)
+)
+
+// BlendMode represents a VGBlendMode, the value of ParamBlendMode.
+type BlendMode int
+
+const (
+	BlendSrc      = BlendMode(0x2000)
+	BlendSrcOver  = BlendMode(0x2001)
+	BlendDstOver  = BlendMode(0x2002)
+	BlendSrcIn    = BlendMode(0x2003)
+	BlendDstIn    = BlendMode(0x2004)
+	BlendMultiply = BlendMode(0x2005)
+	BlendScreen   = BlendMode(0x2006)
+	BlendDarken   = BlendMode(0x2007)
+	BlendLighten  = BlendMode(0x2008)
+	BlendAdditive = BlendMode(0x2009)
+)
+
+// ImageMode represents a VGImageMode, the value of ParamImageMode.
+type ImageMode int
+
+const (
+	DrawImageNormal   = ImageMode(0x1F00)
+	DrawImageMultiply = ImageMode(0x1F01)
+	DrawImageStencil  = ImageMode(0x1F02)
+)
+
+// VG boolean values, for parameters such as ParamScissoring and ParamMasking.
+const (
+	False = 0
+	True  = 1
+)
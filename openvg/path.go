@@ -0,0 +1,121 @@
+//go:build !software
+// +build !software
+
+package openvg
+
+// #include "VG/openvg.h"
+// #include "VG/vgu.h"
+import "C"
+import "unsafe"
+
+// Path wraps a VGPath.
+type Path struct {
+	handle C.VGPath
+}
+
+// CreatePath wraps vgCreatePath for a standard path of float coordinates with
+// all capabilities.
+func CreatePath() (Path, error) {
+	handle := C.vgCreatePath(
+		C.VG_PATH_FORMAT_STANDARD,
+		C.VG_PATH_DATATYPE_F,
+		1, /*scale*/
+		0, /*bias*/
+		0, /*segmentCapacityHint*/
+		0, /*coordCapacityHint*/
+		C.VG_PATH_CAPABILITY_ALL)
+	if handle == C.VG_INVALID_HANDLE {
+		return Path{}, newError("failed to create path", ErrorCode(C.vgGetError()))
+	}
+	return Path{handle}, nil
+}
+
+// Destroy wraps vgDestroyPath.
+func (p Path) Destroy() error {
+	C.vgDestroyPath(p.handle)
+	return checkError("failed to destroy path")
+}
+
+// Clear wraps vgClearPath, removing all segments so the path can be rebuilt.
+func (p Path) Clear() error {
+	C.vgClearPath(p.handle, C.VG_PATH_CAPABILITY_ALL)
+	return checkError("failed to clear path")
+}
+
+// Append wraps vgAppendPathData.
+func (p Path) Append(d *PathData) error {
+	if d.Len() == 0 {
+		return nil
+	}
+	var coords unsafe.Pointer
+	if len(d.coords) > 0 {
+		coords = unsafe.Pointer(&d.coords[0])
+	}
+	C.vgAppendPathData(p.handle, C.VGint(len(d.segments)), (*C.VGubyte)(&d.segments[0]), coords)
+	return checkError("failed to append path data")
+}
+
+// Draw wraps vgDrawPath, filling and/or stroking the path with the current
+// paints.
+func (p Path) Draw(mode PaintMode) error {
+	C.vgDrawPath(p.handle, C.VGbitfield(mode))
+	return checkError("failed to draw path")
+}
+
+// Line wraps vguLine.
+func (p Path) Line(x0, y0, x1, y1 float32) error {
+	return vguError("line", C.vguLine(p.handle, C.VGfloat(x0), C.VGfloat(y0), C.VGfloat(x1), C.VGfloat(y1)))
+}
+
+// Polygon wraps vguPolygon. points holds {x0, y0, x1, y1, ...}.
+func (p Path) Polygon(points []float32, closed bool) error {
+	if len(points) < 2 {
+		return nil
+	}
+	cClosed := C.VGboolean(C.VG_FALSE)
+	if closed {
+		cClosed = C.VG_TRUE
+	}
+	return vguError("polygon", C.vguPolygon(
+		p.handle, (*C.VGfloat)(unsafe.Pointer(&points[0])), C.VGint(len(points)/2), cClosed))
+}
+
+// Rect wraps vguRect.
+func (p Path) Rect(x, y, width, height float32) error {
+	return vguError("rect", C.vguRect(p.handle, C.VGfloat(x), C.VGfloat(y), C.VGfloat(width), C.VGfloat(height)))
+}
+
+// RoundRect wraps vguRoundRect.
+func (p Path) RoundRect(x, y, width, height, arcWidth, arcHeight float32) error {
+	return vguError("round rect", C.vguRoundRect(
+		p.handle, C.VGfloat(x), C.VGfloat(y), C.VGfloat(width), C.VGfloat(height),
+		C.VGfloat(arcWidth), C.VGfloat(arcHeight)))
+}
+
+// Ellipse wraps vguEllipse.
+func (p Path) Ellipse(cx, cy, width, height float32) error {
+	return vguError("ellipse", C.vguEllipse(p.handle, C.VGfloat(cx), C.VGfloat(cy), C.VGfloat(width), C.VGfloat(height)))
+}
+
+// Arc wraps vguArc.
+func (p Path) Arc(x, y, width, height, startAngle, extent float32, arcType ArcType) error {
+	return vguError("arc", C.vguArc(
+		p.handle, C.VGfloat(x), C.VGfloat(y), C.VGfloat(width), C.VGfloat(height),
+		C.VGfloat(startAngle), C.VGfloat(extent), C.VGUArcType(arcType)))
+}
+
+func vguError(shape string, code C.VGUErrorCode) error {
+	if code != C.VGU_NO_ERROR {
+		return newError("failed to add "+shape+" to path", vguErrorCodes[code])
+	}
+	return nil
+}
+
+// vguErrorCodes maps VGUErrorCode values to the equivalent VGErrorCode.
+var vguErrorCodes = map[C.VGUErrorCode]ErrorCode{
+	C.VGU_BAD_HANDLE_ERROR:       BadHandleError,
+	C.VGU_ILLEGAL_ARGUMENT_ERROR: IllegalArgumentError,
+	C.VGU_OUT_OF_MEMORY_ERROR:    OutOfMemoryError,
+	C.VGU_PATH_CAPABILITY_ERROR:  PathCapabilityError,
+	C.VGU_BAD_WARP_ERROR:         BadWarpError,
+}
@@ -0,0 +1,148 @@
+//go:build software
+// +build software
+
+package openvg
+
+// Path wraps a VGPath.
+type Path struct {
+	handle *softPath
+}
+
+type softPath struct {
+	data      PathData
+	destroyed bool
+}
+
+func (p Path) valid() bool {
+	return p.handle != nil && !p.handle.destroyed
+}
+
+// CreatePath wraps vgCreatePath for a standard path of float coordinates with
+// all capabilities.
+func CreatePath() (Path, error) {
+	return Path{&softPath{}}, nil
+}
+
+// Destroy wraps vgDestroyPath.
+func (p Path) Destroy() error {
+	if !p.valid() {
+		return newError("failed to destroy path", BadHandleError)
+	}
+	p.handle.destroyed = true
+	p.handle.data = PathData{}
+	return nil
+}
+
+// Clear wraps vgClearPath, removing all segments so the path can be rebuilt.
+func (p Path) Clear() error {
+	if !p.valid() {
+		return newError("failed to clear path", BadHandleError)
+	}
+	p.handle.data = PathData{}
+	return nil
+}
+
+// Append wraps vgAppendPathData.
+func (p Path) Append(d *PathData) error {
+	if !p.valid() {
+		return newError("failed to append path data", BadHandleError)
+	}
+	p.handle.data.segments = append(p.handle.data.segments, d.segments...)
+	p.handle.data.coords = append(p.handle.data.coords, d.coords...)
+	return nil
+}
+
+// Draw wraps vgDrawPath, filling and/or stroking the path with the current
+// paints.
+func (p Path) Draw(mode PaintMode) error {
+	if !p.valid() {
+		return newError("failed to draw path", BadHandleError)
+	}
+	if mode&^(FillPath|StrokePath) != 0 {
+		return newError("failed to draw path", IllegalArgumentError)
+	}
+	if state.surface == nil {
+		return nil
+	}
+	m := matrices[MatrixPathUserToSurface]
+	lines := p.handle.data.flatten(m)
+	if mode&FillPath != 0 {
+		var polys [][]pt
+		for _, line := range lines {
+			polys = append(polys, line.pts)
+		}
+		fillSurface(polys, m, FillRule(Geti(ParamFillRule)), FillPath)
+	}
+	if mode&StrokePath != 0 {
+		fillSurface(strokeOutline(lines), m, FillRuleNonZero, StrokePath)
+	}
+	return nil
+}
+
+// fillSurface transforms polys from user coordinates by m and fills them on
+// the surface with the paint for the given mode.
+func fillSurface(polys [][]pt, m Matrix, rule FillRule, mode PaintMode) {
+	h := state.surface.Bounds().Dy()
+	coverSurface(polys, m, rule, func(x, y int, coverage float32) {
+		blendPixel(x, y, paintAt(mode, float32(x)+0.5, float32(h-y)-0.5), coverage)
+	})
+}
+
+// coverSurface transforms polys from user coordinates by m and calls plot
+// with the coverage of each surface pixel they touch.
+func coverSurface(polys [][]pt, m Matrix, rule FillRule, plot func(x, y int, coverage float32)) {
+	h := state.surface.Bounds().Dy()
+	surfacePolys := make([][]pt, len(polys))
+	for i, poly := range polys {
+		sp := make([]pt, len(poly))
+		for j, p := range poly {
+			q := p.transform(m)
+			sp[j] = pt{q.x, float32(h) - q.y}
+		}
+		surfacePolys[i] = sp
+	}
+	antialias := RenderingQuality(Geti(ParamRenderingQuality)) != RenderingQualityNonantialiased
+	rasterize(surfacePolys, rule, antialias, state.surface.Bounds().Dx(), h, plot)
+}
+
+// Line wraps vguLine.
+func (p Path) Line(x0, y0, x1, y1 float32) error {
+	return p.Append(new(PathData).Line(x0, y0, x1, y1))
+}
+
+// Polygon wraps vguPolygon. points holds {x0, y0, x1, y1, ...}.
+func (p Path) Polygon(points []float32, closed bool) error {
+	return p.Append(new(PathData).Polygon(points, closed))
+}
+
+// Rect wraps vguRect.
+func (p Path) Rect(x, y, width, height float32) error {
+	if width <= 0 || height <= 0 {
+		return newError("failed to add rect to path", IllegalArgumentError)
+	}
+	return p.Append(new(PathData).Rect(x, y, width, height))
+}
+
+// RoundRect wraps vguRoundRect.
+func (p Path) RoundRect(x, y, width, height, arcWidth, arcHeight float32) error {
+	if width <= 0 || height <= 0 {
+		return newError("failed to add round rect to path", IllegalArgumentError)
+	}
+	return p.Append(new(PathData).RoundRect(x, y, width, height, arcWidth, arcHeight))
+}
+
+// Ellipse wraps vguEllipse.
+func (p Path) Ellipse(cx, cy, width, height float32) error {
+	if width <= 0 || height <= 0 {
+		return newError("failed to add ellipse to path", IllegalArgumentError)
+	}
+	return p.Append(new(PathData).Ellipse(cx, cy, width, height))
+}
+
+// Arc wraps vguArc.
+func (p Path) Arc(x, y, width, height, startAngle, extent float32, arcType ArcType) error {
+	if width <= 0 || height <= 0 || arcType < ArcOpen || arcType > ArcPie {
+		return newError("failed to add arc to path", IllegalArgumentError)
+	}
+	return p.Append(new(PathData).Arc(x, y, width, height, startAngle, extent, arcType))
+}
@@ -0,0 +1,201 @@
+package openvg
+
+import "math"
+
+// PaintMode represents a VGPaintMode bitfield, selecting whether Path.Draw
+// fills and/or strokes the path.
+type PaintMode int
+
+const (
+	StrokePath = PaintMode(1 << 0)
+	FillPath   = PaintMode(1 << 1)
+)
+
+// CapStyle represents a VGCapStyle, the value of ParamStrokeCapStyle.
+type CapStyle int
+
+const (
+	CapButt   = CapStyle(0x1700)
+	CapRound  = CapStyle(0x1701)
+	CapSquare = CapStyle(0x1702)
+)
+
+// JoinStyle represents a VGJoinStyle, the value of ParamStrokeJoinStyle.
+type JoinStyle int
+
+const (
+	JoinMiter = JoinStyle(0x1800)
+	JoinRound = JoinStyle(0x1801)
+	JoinBevel = JoinStyle(0x1802)
+)
+
+// ArcType represents a VGUArcType, the closure of an arc added with Arc.
+type ArcType int
+
+const (
+	ArcOpen  = ArcType(0xF100)
+	ArcChord = ArcType(0xF101)
+	ArcPie   = ArcType(0xF102)
+)
+
+// Absolute path segment commands from VGPathSegment.
+const (
+	segClose = 0 << 1
+	segMove  = 1 << 1
+	segLine  = 2 << 1
+	segQuad  = 5 << 1
+	segCubic = 6 << 1
+)
+
+// segCoords maps a segment command to the number of coordinates it takes.
+var segCoords = map[byte]int{
+	segClose: 0,
+	segMove:  2,
+	segLine:  2,
+	segQuad:  4,
+	segCubic: 6,
+}
+
+// PathData accumulates absolute path segments in the VG_PATH_DATATYPE_F
+// layout accepted by Path.Append. Its methods return d so that calls can be
+// chained. The zero value is an empty path.
+type PathData struct {
+	segments []byte
+	coords   []float32
+}
+
+func (d *PathData) add(seg byte, coords ...float32) *PathData {
+	d.segments = append(d.segments, seg)
+	d.coords = append(d.coords, coords...)
+	return d
+}
+
+// MoveTo starts a new subpath at (x, y).
+func (d *PathData) MoveTo(x, y float32) *PathData {
+	return d.add(segMove, x, y)
+}
+
+// LineTo adds a line to (x, y).
+func (d *PathData) LineTo(x, y float32) *PathData {
+	return d.add(segLine, x, y)
+}
+
+// QuadTo adds a quadratic Bézier curve with control point (x1, y1) ending at
+// (x, y).
+func (d *PathData) QuadTo(x1, y1, x, y float32) *PathData {
+	return d.add(segQuad, x1, y1, x, y)
+}
+
+// CubicTo adds a cubic Bézier curve with control points (x1, y1) and (x2, y2)
+// ending at (x, y).
+func (d *PathData) CubicTo(x1, y1, x2, y2, x, y float32) *PathData {
+	return d.add(segCubic, x1, y1, x2, y2, x, y)
+}
+
+// Close closes the current subpath.
+func (d *PathData) Close() *PathData {
+	return d.add(segClose)
+}
+
+// Len returns the number of segments.
+func (d *PathData) Len() int {
+	return len(d.segments)
+}
+
+// The shape methods below add the same geometry as their VGU counterparts.
+
+// kappa is the distance of the control points of a cubic approximating a
+// quarter circle of radius 1.
+const kappa = 0.5522847498
+
+// Line adds a subpath with a single line, like vguLine.
+func (d *PathData) Line(x0, y0, x1, y1 float32) *PathData {
+	return d.MoveTo(x0, y0).LineTo(x1, y1)
+}
+
+// Polygon adds a subpath through the points {x0, y0, x1, y1, ...}, like
+// vguPolygon.
+func (d *PathData) Polygon(points []float32, closed bool) *PathData {
+	for i := 0; i+1 < len(points); i += 2 {
+		if i == 0 {
+			d.MoveTo(points[0], points[1])
+		} else {
+			d.LineTo(points[i], points[i+1])
+		}
+	}
+	if closed && len(points) >= 2 {
+		d.Close()
+	}
+	return d
+}
+
+// Rect adds a closed rectangle, like vguRect.
+func (d *PathData) Rect(x, y, width, height float32) *PathData {
+	return d.MoveTo(x, y).
+		LineTo(x+width, y).
+		LineTo(x+width, y+height).
+		LineTo(x, y+height).
+		Close()
+}
+
+// RoundRect adds a closed rectangle whose corners are quarter ellipses with
+// the given diameters, like vguRoundRect.
+func (d *PathData) RoundRect(x, y, width, height, arcWidth, arcHeight float32) *PathData {
+	rx := float32(math.Min(float64(arcWidth), float64(width))) / 2
+	ry := float32(math.Min(float64(arcHeight), float64(height))) / 2
+	kx, ky := rx*kappa, ry*kappa
+	return d.MoveTo(x+rx, y).
+		LineTo(x+width-rx, y).
+		CubicTo(x+width-rx+kx, y, x+width, y+ry-ky, x+width, y+ry).
+		LineTo(x+width, y+height-ry).
+		CubicTo(x+width, y+height-ry+ky, x+width-rx+kx, y+height, x+width-rx, y+height).
+		LineTo(x+rx, y+height).
+		CubicTo(x+rx-kx, y+height, x, y+height-ry+ky, x, y+height-ry).
+		LineTo(x, y+ry).
+		CubicTo(x, y+ry-ky, x+rx-kx, y, x+rx, y).
+		Close()
+}
+
+// Ellipse adds a closed ellipse centered at (cx, cy) with the given
+// diameters, like vguEllipse.
+func (d *PathData) Ellipse(cx, cy, width, height float32) *PathData {
+	return d.Arc(cx-width/2, cy-height/2, width, height, 0, 360, ArcOpen).Close()
+}
+
+// Arc adds an elliptical arc within the bounding box (x, y, width, height),
+// starting at angle startAngle and sweeping extent degrees counterclockwise,
+// like vguArc.
+func (d *PathData) Arc(x, y, width, height, startAngle, extent float32, arcType ArcType) *PathData {
+	cx, cy := x+width/2, y+height/2
+	rx, ry := width/2, height/2
+	point := func(deg float64) (float32, float32) {
+		sin, cos := math.Sincos(deg * math.Pi / 180)
+		return cx + rx*float32(cos), cy + ry*float32(sin)
+	}
+	x0, y0 := point(float64(startAngle))
+	if arcType == ArcPie {
+		d.MoveTo(cx, cy).LineTo(x0, y0)
+	} else {
+		d.MoveTo(x0, y0)
+	}
+	// Each piece spans at most 90 degrees so that a cubic approximates it
+	// closely.
+	n := int(math.Ceil(math.Abs(float64(extent)) / 90))
+	step := float64(extent) / float64(n)
+	k := float32(4.0 / 3.0 * math.Tan(step*math.Pi/180/4))
+	a := float64(startAngle)
+	for i := 0; i < n; i++ {
+		sin0, cos0 := math.Sincos(a * math.Pi / 180)
+		sin1, cos1 := math.Sincos((a + step) * math.Pi / 180)
+		x1, y1 := point(a + step)
+		d.CubicTo(
+			cx+rx*(float32(cos0)-k*float32(sin0)), cy+ry*(float32(sin0)+k*float32(cos0)),
+			cx+rx*(float32(cos1)+k*float32(sin1)), cy+ry*(float32(sin1)-k*float32(cos1)),
+			x1, y1)
+		a += step
+	}
+	if arcType == ArcChord || arcType == ArcPie {
+		d.Close()
+	}
+	return d
+}
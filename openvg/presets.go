@@ -0,0 +1,281 @@
+package openvg
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+)
+
+// Filter applies an effect from src to dst, which must be different images.
+type Filter func(dst, src Image) error
+
+// ColorMatrixFilter returns a Filter applying m.
+func ColorMatrixFilter(m ColorMatrix) Filter {
+	return func(dst, src Image) error {
+		return dst.ColorMatrix(src, m)
+	}
+}
+
+// KernelFilter returns a Filter convolving with k, padding the edges.
+func KernelFilter(k Kernel) Filter {
+	return func(dst, src Image) error {
+		return dst.Convolve(src, k, TilePad)
+	}
+}
+
+var (
+	// Grayscale replaces colors with their Rec. 601 luma.
+	Grayscale = ColorMatrixFilter(ColorMatrix{
+		{0.299, 0.587, 0.114, 0, 0},
+		{0.299, 0.587, 0.114, 0, 0},
+		{0.299, 0.587, 0.114, 0, 0},
+		{0, 0, 0, 1, 0},
+	})
+
+	// Sepia tints colors brown, like an old photograph.
+	Sepia = ColorMatrixFilter(ColorMatrix{
+		{0.393, 0.769, 0.189, 0, 0},
+		{0.349, 0.686, 0.168, 0, 0},
+		{0.272, 0.534, 0.131, 0, 0},
+		{0, 0, 0, 1, 0},
+	})
+
+	// Invert replaces colors with their complements.
+	Invert = ColorMatrixFilter(ColorMatrix{
+		{-1, 0, 0, 0, 1},
+		{0, -1, 0, 0, 1},
+		{0, 0, -1, 0, 1},
+		{0, 0, 0, 1, 0},
+	})
+
+	// Sharpen boosts each pixel against its four neighbours.
+	Sharpen = KernelFilter(Kernel{
+		Width:  3,
+		Height: 3,
+		Values: []int16{
+			0, -1, 0,
+			-1, 5, -1,
+			0, -1, 0,
+		},
+	})
+)
+
+// BrightnessContrast returns a Filter that scales colors by contrast about
+// mid-gray and then adds brightness. BrightnessContrast(0, 1) changes nothing.
+func BrightnessContrast(brightness, contrast float32) Filter {
+	offset := brightness + 0.5*(1-contrast)
+	return ColorMatrixFilter(ColorMatrix{
+		{contrast, 0, 0, 0, offset},
+		{0, contrast, 0, 0, offset},
+		{0, 0, contrast, 0, offset},
+		{0, 0, 0, 1, 0},
+	})
+}
+
+// Blur returns a Filter applying a Gaussian blur of the given standard
+// deviation in pixels.
+func Blur(stdDev float32) Filter {
+	return func(dst, src Image) error {
+		return dst.GaussianBlur(src, stdDev, stdDev, TilePad)
+	}
+}
+
+// EdgeDetect keeps the edges of src with a Laplacian kernel, leaving flat areas
+// black. Alpha is copied from src.
+func EdgeDetect(dst, src Image) error {
+	laplacian := Kernel{
+		Width:  3,
+		Height: 3,
+		Values: []int16{
+			-1, -1, -1,
+			-1, 8, -1,
+			-1, -1, -1,
+		},
+	}
+	// The kernel sums to zero, which would clear alpha too.
+	if err := withChannelMask(ChannelAlpha, func() error {
+		return dst.ColorMatrix(src, IdentityColorMatrix)
+	}); err != nil {
+		return err
+	}
+	return withChannelMask(ChannelRGB, func() error {
+		return dst.Convolve(src, laplacian, TilePad)
+	})
+}
+
+// Posterize returns a Filter reducing each color channel to the given number
+// of levels.
+func Posterize(levels int) Filter {
+	if levels < 2 {
+		levels = 2
+	}
+	var table, identity LookupTable
+	for i := range table {
+		level := i * levels / 256
+		table[i] = uint8(level * 0xff / (levels - 1))
+		identity[i] = uint8(i)
+	}
+	return func(dst, src Image) error {
+		return dst.Lookup(src, table, table, table, identity)
+	}
+}
+
+// thermalRamp is the palette of Thermal, from cold to hot.
+var thermalRamp = [...]color.NRGBA{
+	{0x00, 0x00, 0x00, 0xff},
+	{0x20, 0x00, 0x8c, 0xff},
+	{0xcc, 0x00, 0x77, 0xff},
+	{0xff, 0xd7, 0x00, 0xff},
+	{0xff, 0xff, 0xff, 0xff},
+}
+
+// Thermal maps brightness to a false color palette, running from black through
+// purple, red and yellow to white. It reads the green channel, which carries
+// most of the luminance.
+func Thermal(dst, src Image) error {
+	var lut [256]color.NRGBA
+	steps := len(thermalRamp) - 1
+	for i := range lut {
+		t := float32(i) / 0xff * float32(steps)
+		k := int(t)
+		if k >= steps {
+			k = steps - 1
+		}
+		f := t - float32(k)
+		a, b := thermalRamp[k], thermalRamp[k+1]
+		lut[i] = color.NRGBA{
+			uint8(float32(a.R) + (float32(b.R)-float32(a.R))*f),
+			uint8(float32(a.G) + (float32(b.G)-float32(a.G))*f),
+			uint8(float32(a.B) + (float32(b.B)-float32(a.B))*f),
+			0xff,
+		}
+	}
+	return dst.LookupSingle(src, ChannelGreen, &lut)
+}
+
+// withChannelMask calls fn with ParamFilterChannelMask set to mask, restoring
+// the previous mask afterwards.
+func withChannelMask(mask ImageChannel, fn func() error) error {
+	old := Geti(ParamFilterChannelMask)
+	if err := Seti(ParamFilterChannelMask, int(mask)); err != nil {
+		return err
+	}
+	defer Seti(ParamFilterChannelMask, old)
+	return fn()
+}
+
+// Presets are the named filters accepted by ParseFilters.
+var Presets = map[string]Filter{
+	"grayscale": Grayscale,
+	"sepia":     Sepia,
+	"invert":    Invert,
+	"sharpen":   Sharpen,
+	"brighten":  BrightnessContrast(0.2, 1),
+	"contrast":  BrightnessContrast(0, 1.5),
+	"blur":      Blur(4),
+	"privacy":   Blur(12),
+	"edges":     EdgeDetect,
+	"posterize": Posterize(4),
+	"thermal":   Thermal,
+}
+
+// ParseFilters looks up a comma-separated list of Presets, such as
+// "grayscale,blur".
+func ParseFilters(spec string) ([]Filter, error) {
+	var filters []Filter
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		f, ok := Presets[name]
+		if !ok {
+			names := make([]string, 0, len(Presets))
+			for n := range Presets {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("openvg: unknown filter %q, want one of %s", name, strings.Join(names, ", "))
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// FilterChain applies filters in order, passing the intermediate results
+// through scratch images of the size and format of the source. The scratch
+// images are kept between calls to Apply until Destroy.
+type FilterChain struct {
+	filters []Filter
+	scratch [2]Image
+	format  ImageFormat
+	width   int
+	height  int
+}
+
+// NewFilterChain returns a FilterChain applying filters in order.
+func NewFilterChain(filters ...Filter) *FilterChain {
+	return &FilterChain{filters: filters}
+}
+
+// Apply runs the chain from src to dst. The chain must hold at least one
+// filter.
+func (c *FilterChain) Apply(dst, src Image) error {
+	if len(c.filters) == 0 {
+		return fmt.Errorf("openvg: failed to apply filters: empty chain")
+	}
+	if len(c.filters) > 1 {
+		if err := c.ensureScratch(src); err != nil {
+			return err
+		}
+	}
+	in := src
+	for i, f := range c.filters {
+		out := dst
+		if i < len(c.filters)-1 {
+			out = c.scratch[i%2]
+		}
+		if err := f(out, in); err != nil {
+			return err
+		}
+		in = out
+	}
+	return nil
+}
+
+// ensureScratch (re)creates the scratch images to match src.
+func (c *FilterChain) ensureScratch(src Image) error {
+	format, width, height := src.Format(), src.Width(), src.Height()
+	if c.scratch[0] != (Image{}) && format == c.format && width == c.width && height == c.height {
+		return nil
+	}
+	if err := c.Destroy(); err != nil {
+		return err
+	}
+	for i := range c.scratch {
+		img, err := CreateImage(format, width, height, []ImageQuality{ImageQualityFaster})
+		if err != nil {
+			c.Destroy()
+			return err
+		}
+		c.scratch[i] = img
+	}
+	c.format, c.width, c.height = format, width, height
+	return nil
+}
+
+// Destroy destroys the scratch images.
+func (c *FilterChain) Destroy() error {
+	var firstErr error
+	for i, img := range c.scratch {
+		if img == (Image{}) {
+			continue
+		}
+		if err := img.Destroy(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		c.scratch[i] = Image{}
+	}
+	return firstErr
+}
@@ -0,0 +1,105 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// premul is a premultiplied color with components in [0, 1].
+type premul struct {
+	r, g, b, a float32
+}
+
+func toPremul(c color.RGBA) premul {
+	return premul{float32(c.R) / 0xff, float32(c.G) / 0xff, float32(c.B) / 0xff, float32(c.A) / 0xff}
+}
+
+func (p premul) rgba() color.RGBA {
+	return color.RGBA{unitToByte(p.r), unitToByte(p.g), unitToByte(p.b), unitToByte(p.a)}
+}
+
+// scale returns p with every component multiplied by k.
+func (p premul) scale(k float32) premul {
+	return premul{p.r * k, p.g * k, p.b * k, p.a * k}
+}
+
+// blend combines the premultiplied source s with destination d using the
+// equations of the given VG blend mode.
+func blend(mode BlendMode, s, d premul) premul {
+	over := s.a + d.a*(1-s.a)
+	channel := func(sc, dc float32) float32 {
+		switch mode {
+		case BlendSrc:
+			return sc
+		case BlendDstOver:
+			return sc*(1-d.a) + dc
+		case BlendSrcIn:
+			return sc * d.a
+		case BlendDstIn:
+			return dc * s.a
+		case BlendMultiply:
+			return sc*(1-d.a) + dc*(1-s.a) + sc*dc
+		case BlendScreen:
+			return sc + dc - sc*dc
+		case BlendDarken:
+			return float32(math.Min(float64(sc+dc*(1-s.a)), float64(dc+sc*(1-d.a))))
+		case BlendLighten:
+			return float32(math.Max(float64(sc+dc*(1-s.a)), float64(dc+sc*(1-d.a))))
+		case BlendAdditive:
+			return float32(math.Min(float64(sc+dc), 1))
+		default: // BlendSrcOver
+			return sc + dc*(1-s.a)
+		}
+	}
+	r := premul{channel(s.r, d.r), channel(s.g, d.g), channel(s.b, d.b), 0}
+	switch mode {
+	case BlendSrc:
+		r.a = s.a
+	case BlendSrcIn:
+		r.a = s.a * d.a
+	case BlendDstIn:
+		r.a = d.a * s.a
+	case BlendAdditive:
+		r.a = float32(math.Min(float64(s.a+d.a), 1))
+	default:
+		r.a = over
+	}
+	return r
+}
+
+// blendPixel blends s into the surface pixel at (x, y), in top-down
+// coordinates, using the current blend mode. coverage scales the source.
+func blendPixel(x, y int, s premul, coverage float32) {
+	coverage *= clipCoverage(x, y)
+	if coverage <= 0 {
+		return
+	}
+	if coverage < 1 {
+		s = s.scale(coverage)
+	}
+	d := toPremul(state.surface.RGBAAt(x, y))
+	state.surface.SetRGBA(x, y, blend(BlendMode(Geti(ParamBlendMode)), s, d).rgba())
+}
+
+// surfaceBounds returns the top-down bounding box on the surface of the
+// rectangle (0, 0)-(w, h) transformed by m, clipped to the surface.
+func surfaceBounds(m Matrix, w, h float32) image.Rectangle {
+	sb := state.surface.Bounds()
+	minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for _, p := range [][2]float32{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := m.Apply(p[0], p[1])
+		minX = float32(math.Min(float64(minX), float64(x)))
+		minY = float32(math.Min(float64(minY), float64(y)))
+		maxX = float32(math.Max(float64(maxX), float64(x)))
+		maxY = float32(math.Max(float64(maxY), float64(y)))
+	}
+	r := image.Rect(
+		int(math.Floor(float64(minX))), sb.Dy()-int(math.Ceil(float64(maxY))),
+		int(math.Ceil(float64(maxX))), sb.Dy()-int(math.Floor(float64(minY))))
+	return r.Intersect(sb)
+}
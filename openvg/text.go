@@ -0,0 +1,203 @@
+package openvg
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font renders text with glyph outlines parsed from a TrueType or OpenType
+// font. Each glyph is converted into a Path in font units the first time it is
+// drawn and cached until Destroy.
+type Font struct {
+	font       *sfnt.Font
+	buf        sfnt.Buffer
+	unitsPerEm float32
+	glyphs     map[sfnt.GlyphIndex]glyph
+}
+
+type glyph struct {
+	path    Path
+	empty   bool
+	advance float32
+}
+
+// LoadFont parses a TrueType or OpenType font.
+func LoadFont(data []byte) (*Font, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("openvg: failed to parse font: %v", err)
+	}
+	return &Font{
+		font:       f,
+		unitsPerEm: float32(f.UnitsPerEm()),
+		glyphs:     map[sfnt.GlyphIndex]glyph{},
+	}, nil
+}
+
+// LoadFontFile reads and parses a TrueType or OpenType font file.
+func LoadFontFile(filename string) (*Font, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("openvg: failed to read font: %v", err)
+	}
+	return LoadFont(data)
+}
+
+// Destroy destroys the cached glyph paths.
+func (f *Font) Destroy() error {
+	var firstErr error
+	for i, g := range f.glyphs {
+		if !g.empty {
+			if err := g.path.Destroy(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		delete(f.glyphs, i)
+	}
+	return firstErr
+}
+
+// ppem is the size glyphs are loaded at, so that outlines are in font units.
+func (f *Font) ppem() fixed.Int26_6 {
+	return fixed.Int26_6(f.unitsPerEm * 64)
+}
+
+// glyph returns the cached glyph for index i, loading it if needed.
+func (f *Font) glyph(i sfnt.GlyphIndex) (glyph, error) {
+	if g, ok := f.glyphs[i]; ok {
+		return g, nil
+	}
+	advance, err := f.font.GlyphAdvance(&f.buf, i, f.ppem(), font.HintingNone)
+	if err != nil {
+		return glyph{}, fmt.Errorf("openvg: failed to load glyph %d: %v", i, err)
+	}
+	segments, err := f.font.LoadGlyph(&f.buf, i, f.ppem(), nil)
+	if err != nil {
+		return glyph{}, fmt.Errorf("openvg: failed to load glyph %d: %v", i, err)
+	}
+	g := glyph{advance: fromFixed(advance), empty: len(segments) == 0}
+	if !g.empty {
+		var d PathData
+		// sfnt outlines have the y axis pointing down.
+		pt := func(p fixed.Point26_6) (float32, float32) {
+			return fromFixed(p.X), -fromFixed(p.Y)
+		}
+		for _, s := range segments {
+			x0, y0 := pt(s.Args[0])
+			switch s.Op {
+			case sfnt.SegmentOpMoveTo:
+				if d.Len() > 0 {
+					d.Close()
+				}
+				d.MoveTo(x0, y0)
+			case sfnt.SegmentOpLineTo:
+				d.LineTo(x0, y0)
+			case sfnt.SegmentOpQuadTo:
+				x1, y1 := pt(s.Args[1])
+				d.QuadTo(x0, y0, x1, y1)
+			case sfnt.SegmentOpCubeTo:
+				x1, y1 := pt(s.Args[1])
+				x2, y2 := pt(s.Args[2])
+				d.CubicTo(x0, y0, x1, y1, x2, y2)
+			}
+		}
+		d.Close()
+		if g.path, err = CreatePath(); err != nil {
+			return glyph{}, err
+		}
+		if err := g.path.Append(&d); err != nil {
+			g.path.Destroy()
+			return glyph{}, err
+		}
+	}
+	f.glyphs[i] = g
+	return g, nil
+}
+
+func fromFixed(v fixed.Int26_6) float32 {
+	return float32(v) / 64
+}
+
+// layout calls fn with every glyph of text and its pen position in font
+// units, applying kerning.
+func (f *Font) layout(text string, fn func(g glyph, pen float32) error) (float32, error) {
+	var pen float32
+	prev := sfnt.GlyphIndex(0)
+	for _, r := range text {
+		i, err := f.font.GlyphIndex(&f.buf, r)
+		if err != nil {
+			return 0, fmt.Errorf("openvg: failed to find glyph for %q: %v", r, err)
+		}
+		if prev != 0 {
+			if kern, err := f.font.Kern(&f.buf, prev, i, f.ppem(), font.HintingNone); err == nil {
+				pen += fromFixed(kern)
+			}
+		}
+		g, err := f.glyph(i)
+		if err != nil {
+			return 0, err
+		}
+		if err := fn(g, pen); err != nil {
+			return 0, err
+		}
+		pen += g.advance
+		prev = i
+	}
+	return pen, nil
+}
+
+// MeasureText returns the advance width of text at the given size, in the
+// same units as size.
+func (f *Font) MeasureText(size float32, text string) (float32, error) {
+	width, err := f.layout(text, func(glyph, float32) error { return nil })
+	return width * size / f.unitsPerEm, err
+}
+
+// Metrics returns the ascent and descent of the font at the given size. Both
+// are positive distances from the baseline.
+func (f *Font) Metrics(size float32) (ascent, descent float32, err error) {
+	m, err := f.font.Metrics(&f.buf, f.ppem(), font.HintingNone)
+	if err != nil {
+		return 0, 0, fmt.Errorf("openvg: failed to read font metrics: %v", err)
+	}
+	scale := size / f.unitsPerEm
+	return fromFixed(m.Ascent) * scale, fromFixed(m.Descent) * scale, nil
+}
+
+// DrawText fills text with paint, with the baseline starting at (x, y) in
+// path user coordinates; size is the em size in the same units. Glyphs are
+// filled with the non-zero rule that TrueType outlines assume. The fill rule
+// and the path user-to-surface matrix are restored before returning, and paint
+// is left set as the fill paint.
+func (f *Font) DrawText(x, y, size float32, text string, paint Paint) error {
+	if err := paint.Set(FillPath); err != nil {
+		return err
+	}
+	rule := Geti(ParamFillRule)
+	if err := Seti(ParamFillRule, int(FillRuleNonZero)); err != nil {
+		return err
+	}
+	defer Seti(ParamFillRule, rule)
+	mode := MatrixMode(Geti(ParamMatrixMode))
+	defer SetMatrixMode(mode)
+	if err := SetMatrixMode(MatrixPathUserToSurface); err != nil {
+		return err
+	}
+	base := GetMatrix()
+	defer LoadMatrix(base)
+	scale := size / f.unitsPerEm
+	_, err := f.layout(text, func(g glyph, pen float32) error {
+		if g.empty {
+			return nil
+		}
+		if err := LoadMatrix(base.Translated(x+pen*scale, y).Scaled(scale, scale)); err != nil {
+			return err
+		}
+		return g.path.Draw(FillPath)
+	})
+	return err
+}
@@ -0,0 +1,54 @@
+//go:build software
+// +build software
+
+package openvg
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestDrawTextFillsOverlappingContours(t *testing.T) {
+	surface := bindTestSurface(t, 16, 16)
+	f, err := LoadFont(goregular.TTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	defer f.Destroy()
+
+	// Replace the glyph of "x" with two overlapping squares wound the same
+	// way, as TrueType glyphs built from overlapping strokes are.
+	i, err := f.font.GlyphIndex(&f.buf, 'x')
+	if err != nil {
+		t.Fatalf("GlyphIndex: %v", err)
+	}
+	path, err := CreatePath()
+	if err != nil {
+		t.Fatalf("CreatePath: %v", err)
+	}
+	var d PathData
+	d.Rect(0, 0, 8, 8).Rect(4, 4, 8, 8)
+	if err := path.Append(&d); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	f.glyphs[i] = glyph{path: path, advance: 8}
+
+	paint, err := NewColorPaint(1, 1, 1, 1)
+	if err != nil {
+		t.Fatalf("NewColorPaint: %v", err)
+	}
+	defer paint.Destroy()
+	Seti(ParamFillRule, int(FillRuleEvenOdd))
+	// At an em size of unitsPerEm, font units are surface pixels.
+	if err := f.DrawText(0, 0, f.unitsPerEm, "x", paint); err != nil {
+		t.Fatalf("DrawText: %v", err)
+	}
+	checkPixel(t, surface, 2, 2, white)
+	checkPixel(t, surface, 6, 6, white)
+	checkPixel(t, surface, 10, 10, white)
+	checkPixel(t, surface, 14, 14, transparent)
+	if rule := FillRule(Geti(ParamFillRule)); rule != FillRuleEvenOdd {
+		t.Errorf("fill rule after DrawText = %#x, want %#x", int(rule), int(FillRuleEvenOdd))
+	}
+}
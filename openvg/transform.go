@@ -0,0 +1,62 @@
+//go:build !software
+// +build !software
+
+package openvg
+
+// #include "VG/openvg.h"
+import "C"
+import "unsafe"
+
+// The transform functions operate on the matrix selected by ParamMatrixMode,
+// see SetMatrixMode.
+
+// LoadIdentity wraps vgLoadIdentity.
+func LoadIdentity() {
+	C.vgLoadIdentity()
+	debugCheck("failed to load identity")
+}
+
+// Translate wraps vgTranslate.
+func Translate(tx, ty float32) {
+	C.vgTranslate(C.VGfloat(tx), C.VGfloat(ty))
+	debugCheck("failed to translate")
+}
+
+// Scale wraps vgScale.
+func Scale(sx, sy float32) {
+	C.vgScale(C.VGfloat(sx), C.VGfloat(sy))
+	debugCheck("failed to scale")
+}
+
+// Shear wraps vgShear.
+func Shear(shx, shy float32) {
+	C.vgShear(C.VGfloat(shx), C.VGfloat(shy))
+	debugCheck("failed to shear")
+}
+
+// Rotate wraps vgRotate. The angle is in degrees, counterclockwise.
+func Rotate(angle float32) {
+	C.vgRotate(C.VGfloat(angle))
+	debugCheck("failed to rotate")
+}
+
+// LoadMatrix wraps vgLoadMatrix. Matrices other than the image matrix must be
+// affine; the last row is ignored for them.
+func LoadMatrix(m Matrix) error {
+	C.vgLoadMatrix((*C.VGfloat)(unsafe.Pointer(&m[0])))
+	return checkError("failed to load matrix")
+}
+
+// GetMatrix wraps vgGetMatrix.
+func GetMatrix() Matrix {
+	var m Matrix
+	C.vgGetMatrix((*C.VGfloat)(unsafe.Pointer(&m[0])))
+	debugCheck("failed to get matrix")
+	return m
+}
+
+// MultMatrix wraps vgMultMatrix.
+func MultMatrix(m Matrix) error {
+	C.vgMultMatrix((*C.VGfloat)(unsafe.Pointer(&m[0])))
+	return checkError("failed to multiply matrix")
+}
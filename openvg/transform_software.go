@@ -0,0 +1,73 @@
+//go:build software
+// +build software
+
+package openvg
+
+// matrices holds the matrix for each matrix mode.
+var matrices = map[MatrixMode]Matrix{
+	MatrixPathUserToSurface:  Identity,
+	MatrixImageUserToSurface: Identity,
+	MatrixFillPaintToUser:    Identity,
+	MatrixStrokePaintToUser:  Identity,
+	MatrixGlyphUserToSurface: Identity,
+}
+
+func currentMatrixMode() MatrixMode {
+	return MatrixMode(Geti(ParamMatrixMode))
+}
+
+// setCurrent stores m as the current matrix, forcing the last row of every
+// matrix but the image matrix to be affine as VG does.
+func setCurrent(m Matrix) {
+	mode := currentMatrixMode()
+	if mode != MatrixImageUserToSurface {
+		m[2], m[5], m[8] = 0, 0, 1
+	}
+	matrices[mode] = m
+}
+
+// LoadIdentity wraps vgLoadIdentity.
+func LoadIdentity() {
+	setCurrent(Identity)
+}
+
+// Translate wraps vgTranslate.
+func Translate(tx, ty float32) {
+	setCurrent(GetMatrix().Translated(tx, ty))
+}
+
+// Scale wraps vgScale.
+func Scale(sx, sy float32) {
+	setCurrent(GetMatrix().Scaled(sx, sy))
+}
+
+// Shear wraps vgShear.
+func Shear(shx, shy float32) {
+	setCurrent(GetMatrix().Sheared(shx, shy))
+}
+
+// Rotate wraps vgRotate. The angle is in degrees, counterclockwise.
+func Rotate(angle float32) {
+	setCurrent(GetMatrix().Rotated(angle))
+}
+
+// LoadMatrix wraps vgLoadMatrix. Matrices other than the image matrix must be
+// affine; the last row is ignored for them.
+func LoadMatrix(m Matrix) error {
+	setCurrent(m)
+	return nil
+}
+
+// GetMatrix wraps vgGetMatrix.
+func GetMatrix() Matrix {
+	return matrices[currentMatrixMode()]
+}
+
+// MultMatrix wraps vgMultMatrix.
+func MultMatrix(m Matrix) error {
+	if currentMatrixMode() != MatrixImageUserToSurface && (m[2] != 0 || m[5] != 0 || m[8] != 1) {
+		return newError("failed to multiply matrix", IllegalArgumentError)
+	}
+	setCurrent(GetMatrix().Mul(m))
+	return nil
+}
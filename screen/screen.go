@@ -0,0 +1,212 @@
+// Package screen sets up a full-screen EGL window surface on a dispmanx
+// display for OpenVG drawing. Built with -tags software, it runs against the
+// software bcmhost and egl backends, which need no device; the presented
+// frames can then be read with Surface().Frame().
+package screen
+
+import (
+	"fmt"
+
+	"../bcmhost"
+	"../egl"
+)
+
+// Options configures Open. The zero Options opens the main LCD with the
+// default EGL config.
+type Options struct {
+	// Display is the dispmanx display to open, e.g. bcmhost.DispmanxIDMainLcd.
+	Display int
+	// Layer is the dispmanx layer of the screen's element. Higher layers are
+	// drawn on top.
+	Layer int
+	// Config selects the EGL config. Nil selects egl.DefaultConfigAttribs.
+	Config *egl.ConfigAttribs
+	// Samples, if positive, ranks the matching configs with
+	// egl.PreferSamples(Samples) instead of taking EGL's preferred one.
+	Samples int
+}
+
+// Screen owns the dispmanx display and element, the EGL display, surface and
+// context created by Open. The context is current on the thread that called
+// Open, which must do all drawing; see runtime.LockOSThread.
+type Screen struct {
+	width, height int
+	window        bcmhost.DispmanxWindow
+	display       egl.Display
+	config        egl.Config
+	surface       egl.Surface
+	context       egl.Context
+	// closers tears down what Open set up, in the order it was set up.
+	closers []func() error
+}
+
+// Open sets up the screen. If a step fails, the steps before it are undone in
+// reverse order.
+func Open(opts Options) (*Screen, error) {
+	s := &Screen{}
+	if err := s.open(opts); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Screen) open(opts Options) (err error) {
+	bcmhost.Init()
+	s.onClose(func() error {
+		bcmhost.Deinit()
+		return nil
+	})
+	if s.width, s.height, err = bcmhost.GraphicsGetDisplaySize(opts.Display); err != nil {
+		return fmt.Errorf("bcmhost: %w", err)
+	}
+	dispmanx, err := bcmhost.DispmanxDisplayOpen(opts.Display)
+	if err != nil {
+		return fmt.Errorf("bcmhost: %w", err)
+	}
+	s.onClose(dispmanx.Close)
+	update, err := dispmanx.UpdateStart(0 /*priority*/)
+	if err != nil {
+		return fmt.Errorf("bcmhost: %w", err)
+	}
+	// An update left open blocks the next one, so submit it even if a later
+	// step fails.
+	submitted := false
+	defer func() {
+		if !submitted {
+			update.UpdateSubmit()
+		}
+	}()
+	element, err := update.ElementAdd(
+		opts.Layer,
+		bcmhost.Rect{Width: s.width, Height: s.height},
+		bcmhost.DispmanxDefaultResource,
+		bcmhost.Rect{Width: s.width << 16, Height: s.height << 16},
+		bcmhost.DispmanxProtectionNone)
+	if err != nil {
+		return fmt.Errorf("bcmhost: %w", err)
+	}
+	s.onClose(func() error { return removeElement(dispmanx, element) })
+
+	if s.display, err = egl.GetDisplay(egl.DefaultDisplay); err != nil {
+		return fmt.Errorf("egl: %w", err)
+	}
+	if _, err = s.display.Initialize(); err != nil {
+		return fmt.Errorf("egl: %w", err)
+	}
+	s.onClose(egl.ReleaseThread)
+	s.onClose(s.display.Terminate)
+	if err = egl.BindAPI(egl.APIOpenVG); err != nil {
+		return fmt.Errorf("egl: %w", err)
+	}
+	if s.config, err = s.chooseConfig(opts); err != nil {
+		return fmt.Errorf("egl: %w", err)
+	}
+	s.window = bcmhost.NewDispmanxWindow(element, s.width, s.height)
+	if s.surface, err = s.display.CreateWindowSurface(s.config, s.window); err != nil {
+		return fmt.Errorf("egl: %w", err)
+	}
+	s.onClose(func() error { return s.display.DestroySurface(s.surface) })
+	if s.context, err = s.display.CreateContext(s.config); err != nil {
+		return fmt.Errorf("egl: %w", err)
+	}
+	s.onClose(func() error { return s.display.DestroyContext(s.context) })
+	if err = s.display.MakeCurrent(s.surface, s.context); err != nil {
+		return fmt.Errorf("egl: %w", err)
+	}
+	s.onClose(s.display.ReleaseCurrent)
+
+	submitted = true
+	if err = update.UpdateSubmit(); err != nil {
+		return fmt.Errorf("bcmhost: %w", err)
+	}
+	return nil
+}
+
+// removeElement removes element from display in an update of its own.
+func removeElement(display bcmhost.DispmanxDisplay, element bcmhost.DispmanxElement) error {
+	update, err := display.UpdateStart(0 /*priority*/)
+	if err != nil {
+		return fmt.Errorf("bcmhost: %w", err)
+	}
+	if err := update.ElementRemove(element); err != nil {
+		update.UpdateSubmit()
+		return fmt.Errorf("bcmhost: %w", err)
+	}
+	if err := update.UpdateSubmit(); err != nil {
+		return fmt.Errorf("bcmhost: %w", err)
+	}
+	return nil
+}
+
+func (s *Screen) chooseConfig(opts Options) (egl.Config, error) {
+	attribs := opts.Config
+	if attribs == nil {
+		attribs = egl.DefaultConfigAttribs()
+	}
+	configs, err := s.display.ChooseConfigs(attribs)
+	if err != nil {
+		return egl.Config{}, err
+	}
+	if len(configs) == 0 {
+		return egl.Config{}, egl.ErrNoMatchingConfig
+	}
+	if opts.Samples > 0 {
+		egl.Rank(configs, egl.PreferSamples(opts.Samples))
+	}
+	return configs[0], nil
+}
+
+func (s *Screen) onClose(f func() error) {
+	s.closers = append(s.closers, f)
+}
+
+// Width returns the width of the screen in pixels.
+func (s *Screen) Width() int {
+	return s.width
+}
+
+// Height returns the height of the screen in pixels.
+func (s *Screen) Height() int {
+	return s.height
+}
+
+// Display returns the EGL display of the screen.
+func (s *Screen) Display() egl.Display {
+	return s.display
+}
+
+// Config returns the EGL config of the screen's surface and context.
+func (s *Screen) Config() egl.Config {
+	return s.config
+}
+
+// Surface returns the EGL window surface of the screen.
+func (s *Screen) Surface() egl.Surface {
+	return s.surface
+}
+
+// Swap presents the frame drawn since the last Swap.
+func (s *Screen) Swap() error {
+	return s.display.SwapBuffers(s.surface)
+}
+
+// SetSwapInterval sets the minimum number of video frames between swaps: 0
+// disables vsync and 1, the default, swaps once per frame.
+func (s *Screen) SetSwapInterval(interval int) error {
+	return s.display.SwapInterval(interval)
+}
+
+// Close releases the context and destroys everything Open created, in reverse
+// order. It returns the first error, but keeps tearing down after one. Calling
+// Close again does nothing.
+func (s *Screen) Close() error {
+	var firstErr error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if err := s.closers[i](); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	s.closers = nil
+	return firstErr
+}
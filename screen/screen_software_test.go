@@ -0,0 +1,88 @@
+//go:build software
+// +build software
+
+package screen
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"../bcmhost"
+	"../egl"
+	"../openvg"
+)
+
+// setDisplaySize shrinks the software display for a test.
+func setDisplaySize(t *testing.T, width, height int) {
+	t.Helper()
+	w, h := bcmhost.DisplayWidth, bcmhost.DisplayHeight
+	bcmhost.DisplayWidth, bcmhost.DisplayHeight = width, height
+	t.Cleanup(func() { bcmhost.DisplayWidth, bcmhost.DisplayHeight = w, h })
+}
+
+func TestOpenSwapClose(t *testing.T) {
+	setDisplaySize(t, 8, 6)
+	elements := bcmhost.ElementCount()
+	// Open a second time to check that Close released everything.
+	for i := 0; i < 2; i++ {
+		scr, err := Open(Options{Layer: 1})
+		if err != nil {
+			t.Fatalf("Open #%d: %v", i+1, err)
+		}
+		if scr.Width() != 8 || scr.Height() != 6 {
+			t.Errorf("screen is %dx%d, want 8x6", scr.Width(), scr.Height())
+		}
+		if n := bcmhost.ElementCount(); n != elements+1 {
+			t.Errorf("Open added %d elements, want 1", n-elements)
+		}
+		openvg.SetClearColor(1, 0, 0, 1)
+		if err := openvg.Clear(0, 0, scr.Width(), scr.Height()); err != nil {
+			t.Fatalf("Clear: %v", err)
+		}
+		if err := scr.Swap(); err != nil {
+			t.Fatalf("Swap: %v", err)
+		}
+		frame := scr.Surface().Frame()
+		if frame == nil {
+			t.Fatal("no frame presented after Swap")
+		}
+		if b := frame.Bounds(); b.Dx() != 8 || b.Dy() != 6 {
+			t.Errorf("frame is %v, want 8x6", b)
+		}
+		red := color.RGBA{0xff, 0, 0, 0xff}
+		for _, p := range [][2]int{{0, 0}, {7, 0}, {0, 5}, {7, 5}} {
+			if c := frame.RGBAAt(p[0], p[1]); c != red {
+				t.Errorf("frame pixel %v = %v, want %v", p, c, red)
+			}
+		}
+		if err := scr.Close(); err != nil {
+			t.Fatalf("Close #%d: %v", i+1, err)
+		}
+		if n := bcmhost.ElementCount(); n != elements {
+			t.Errorf("%d elements left after Close, want 0", n-elements)
+		}
+		if err := scr.Close(); err != nil {
+			t.Errorf("second Close: %v", err)
+		}
+	}
+}
+
+func TestOpenUndoesFailedSetup(t *testing.T) {
+	setDisplaySize(t, 8, 6)
+	elements := bcmhost.ElementCount()
+	// No software config has 16 samples, so Open fails after adding the
+	// element.
+	_, err := Open(Options{Config: egl.NewConfigAttribs().Samples(16)})
+	if !errors.Is(err, egl.ErrNoMatchingConfig) {
+		t.Fatalf("Open returned %v, want %v", err, egl.ErrNoMatchingConfig)
+	}
+	if n := bcmhost.ElementCount(); n != elements {
+		t.Errorf("%d elements left after a failed Open, want 0", n-elements)
+	}
+	scr, err := Open(Options{})
+	if err != nil {
+		t.Fatalf("Open after a failed Open: %v", err)
+	}
+	scr.Close()
+}